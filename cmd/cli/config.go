@@ -3,12 +3,18 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
@@ -18,27 +24,43 @@ type Config struct {
 	Kid                    string   `json:"kid"`
 	Aud                    string   `json:"aud"`
 	RedirectURIs           []string `json:"redirect_uris"`
+	Scopes                 []string `json:"scopes"`
 	Issuer                 string   `json:"issuer"`
 	SigningKeyPEM          string   `json:"private_key"`
 	TransportRootCAsPEM    []string `json:"transport_root_cas"`
 	TransportCertSubjectDN string   `json:"transport_cert_subject_dn"`
 	TransportCertPEM       string   `json:"transport_cert"`
 	TransportKeyPEM        string   `json:"transport_key"`
+	TransportPKCS12        string   `json:"transport_pkcs12"`
 	GetImplemented         bool     `json:"get_implemented"`
 	PutImplemented         bool     `json:"put_implemented"`
 	DeleteImplemented      bool     `json:"delete_implemented"`
 	Environment            string   `json:"environment"`
 	Brand                  string   `json:"brand"`
+	// DiscoveryDocument is a local discovery document file used instead of fetching the well-known endpoint
+	DiscoveryDocument string `json:"discovery_document"`
+	// DiscoveryOverrides replace discovery metadata properties, ex: `token_endpoint`
+	DiscoveryOverrides map[string]interface{} `json:"discovery_overrides"`
+	// ProxyURL is the http proxy for requests to the ASPSP, except to the NoProxy hosts
+	ProxyURL string `json:"proxy_url"`
+	NoProxy  string `json:"no_proxy"`
+	// Headers are added to every request to the ASPSP, ex: `x-fapi-financial-id`
+	Headers map[string]string `json:"headers"`
+	// Hosts maps ASPSP host names to IP addresses, for environments without DNS records
+	Hosts map[string]string `json:"hosts"`
+	// ServerCertExpiryDays fails the TLS scenario when an ASPSP server certificate expires sooner, defaults to 30
+	ServerCertExpiryDays int `json:"server_cert_expiry_days"`
 }
 
-func LoadConfig(configFilePath string) (Config, error) {
+// LoadConfig reads a JSON or YAML config file, selecting the named profile when the file defines profiles
+func LoadConfig(configFilePath, profile string) (Config, error) {
 	f, err := os.Open(configFilePath)
 	if err != nil {
 		return Config{}, errors.Wrap(err, "load config")
 	}
 	defer f.Close()
 
-	config, err := parseConfig(f)
+	config, err := parseConfig(f, configFormat(configFilePath), profile, os.Getenv)
 	if err != nil {
 		return Config{}, errors.Wrap(err, "load config")
 	}
@@ -48,21 +70,286 @@ func LoadConfig(configFilePath string) (Config, error) {
 		return Config{}, errors.Wrap(err, "load config")
 	}
 
+	configDir := filepath.Dir(configFilePath)
+	config, err = resolveKeys(config, configDir, os.Getenv)
+	if err != nil {
+		return Config{}, errors.Wrap(err, "load config")
+	}
+	if config.DiscoveryDocument != "" {
+		config.DiscoveryDocument = configRelativePath(config.DiscoveryDocument, configDir)
+	}
+
 	return config, nil
 }
 
-func parseConfig(f io.Reader) (Config, error) {
+// configRelativePath resolves a relative path from the config file directory
+func configRelativePath(path, configDir string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(configDir, path)
+}
+
+// discoveryOverrideNames are the sorted discovery metadata properties overridden by the config
+func discoveryOverrideNames(config Config) []string {
+	names := make([]string, 0, len(config.DiscoveryOverrides))
+	for name := range config.DiscoveryOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Passwords of encrypted keys and pkcs12 bundles are read from the environment so they aren't in the config file
+const (
+	signingKeyPasswordEnv   = "DCR_PRIVATE_KEY_PASSWORD"
+	transportKeyPasswordEnv = "DCR_TRANSPORT_KEY_PASSWORD"
+)
+
+// resolveKeys reads the keys and certs given as file paths or `env:` references,
+// loads the transport cert and key from a `.p12`/`.pfx` bundle when configured
+// and decrypts password protected private keys. Relative paths are resolved from the config file directory.
+func resolveKeys(config Config, configDir string, getenv func(string) string) (Config, error) {
+	config, err := resolveReferences(config, configDir, getenv)
+	if err != nil {
+		return Config{}, err
+	}
+
+	transportPassword := getenv(transportKeyPasswordEnv)
+	if config.TransportPKCS12 != "" {
+		var bundle []byte
+		bundle, err = ioutil.ReadFile(configRelativePath(config.TransportPKCS12, configDir))
+		if err != nil {
+			return Config{}, errors.Wrap(err, "reading transport pkcs12 bundle")
+		}
+		config.TransportCertPEM, config.TransportKeyPEM, err = certs.ParsePKCS12(bundle, transportPassword)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	signingKeyPEM, err := certs.DecryptPEM([]byte(config.SigningKeyPEM), getenv(signingKeyPasswordEnv))
+	if err != nil {
+		return Config{}, errors.Wrap(err, "private_key")
+	}
+	config.SigningKeyPEM = string(signingKeyPEM)
+
+	transportKeyPEM, err := certs.DecryptPEM([]byte(config.TransportKeyPEM), transportPassword)
+	if err != nil {
+		return Config{}, errors.Wrap(err, "transport_key")
+	}
+	config.TransportKeyPEM = string(transportKeyPEM)
+
+	return config, nil
+}
+
+func resolveReferences(config Config, configDir string, getenv func(string) string) (Config, error) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"private_key", &config.SigningKeyPEM},
+		{"transport_cert", &config.TransportCertPEM},
+		{"transport_key", &config.TransportKeyPEM},
+	}
+	for _, field := range fields {
+		resolved, err := certs.ResolvePEM(*field.value, configDir, getenv)
+		if err != nil {
+			return Config{}, errors.Wrap(err, field.name)
+		}
+		*field.value = resolved
+	}
+
+	// a single root CA reference can hold several certificates, ie: root and issuing CAs
+	var rootCAs []string
+	for key, rootCA := range config.TransportRootCAsPEM {
+		resolved, err := certs.ResolvePEM(rootCA, configDir, getenv)
+		if err != nil {
+			return Config{}, errors.Wrapf(err, "transport_root_cas %d", key)
+		}
+		chain, err := certs.SplitCertificateChain([]byte(resolved))
+		if err != nil {
+			return Config{}, errors.Wrapf(err, "transport_root_cas %d", key)
+		}
+		rootCAs = append(rootCAs, chain...)
+	}
+	config.TransportRootCAsPEM = rootCAs
+
+	return config, nil
+}
+
+const (
+	jsonFormat = "json"
+	yamlFormat = "yaml"
+
+	// profilesKey holds named profiles, each overriding the top level properties shared as defaults
+	profilesKey = "profiles"
+)
+
+// configFormat is YAML for `.yaml` and `.yml` files, JSON otherwise
+func configFormat(configFilePath string) string {
+	switch strings.ToLower(filepath.Ext(configFilePath)) {
+	case ".yaml", ".yml":
+		return yamlFormat
+	default:
+		return jsonFormat
+	}
+}
+
+// LoadProfileNames lists the profiles defined in a config file, empty when the file doesn't define profiles
+func LoadProfileNames(configFilePath string) ([]string, error) {
+	f, err := os.Open(configFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "load config")
+	}
+	defer f.Close()
+
+	properties, err := readProperties(f, configFormat(configFilePath))
+	if err != nil {
+		return nil, errors.Wrap(err, "load config")
+	}
+
+	profiles, ok := properties[profilesKey].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	return profileNames(profiles), nil
+}
+
+func parseConfig(f io.Reader, format, profile string, getenv func(string) string) (Config, error) {
 	var cfg Config
-	rawCfg, err := ioutil.ReadAll(f)
+	properties, err := readProperties(f, format)
 	if err != nil {
-		return cfg, errors.Wrap(err, "unable to read config file contents")
+		return cfg, err
 	}
-	if err = json.NewDecoder(bytes.NewBuffer(rawCfg)).Decode(&cfg); err != nil {
-		return cfg, errors.Wrap(err, "unable to json decode file contents")
+
+	properties, err = selectProfile(properties, profile)
+	if err != nil {
+		return cfg, err
+	}
+
+	for key, value := range properties {
+		properties[key], err = interpolate(value, getenv)
+		if err != nil {
+			return cfg, errors.Wrap(err, key)
+		}
+	}
+
+	// properties are decoded to the config with the json tags regardless of the file format
+	rawProperties, err := json.Marshal(properties)
+	if err != nil {
+		return cfg, errors.Wrap(err, "unable to decode config properties")
+	}
+	if err = json.Unmarshal(rawProperties, &cfg); err != nil {
+		return cfg, errors.Wrap(err, "unable to decode config properties")
 	}
 	return cfg, nil
 }
 
+func readProperties(f io.Reader, format string) (map[string]interface{}, error) {
+	rawCfg, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read config file contents")
+	}
+
+	properties := map[string]interface{}{}
+	if format == yamlFormat {
+		if err = yaml.Unmarshal(rawCfg, &properties); err != nil {
+			return nil, errors.Wrap(err, "unable to yaml decode file contents")
+		}
+	} else {
+		if err = json.NewDecoder(bytes.NewBuffer(rawCfg)).Decode(&properties); err != nil {
+			return nil, errors.Wrap(err, "unable to json decode file contents")
+		}
+	}
+	return properties, nil
+}
+
+// selectProfile merges the properties of the selected profile over the top level default properties
+func selectProfile(properties map[string]interface{}, profile string) (map[string]interface{}, error) {
+	rawProfiles, hasProfiles := properties[profilesKey]
+	delete(properties, profilesKey)
+	if !hasProfiles {
+		if profile != "" {
+			return nil, errors.Errorf("profile %s not found, config doesn't define profiles", profile)
+		}
+		return properties, nil
+	}
+
+	profiles, ok := rawProfiles.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("config property `profiles` should map profile names to properties")
+	}
+	available := strings.Join(profileNames(profiles), ", ")
+	if profile == "" {
+		return nil, errors.Errorf("config defines profiles, select one with -profile: %s", available)
+	}
+	rawProfile, ok := profiles[profile]
+	if !ok {
+		return nil, errors.Errorf("profile %s not found, available profiles: %s", profile, available)
+	}
+	overrides, ok := rawProfile.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("profile %s should map config properties to values", profile)
+	}
+
+	for key, value := range overrides {
+		properties[key] = value
+	}
+	return properties, nil
+}
+
+func profileNames(profiles map[string]interface{}) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// envVariablePattern matches `${VAR}` references in config property values
+// nolint:gochecknoglobals
+var envVariablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolate replaces `${VAR}` references in string values, including strings nested in lists and objects,
+// with the value of the environment variable
+func interpolate(value interface{}, getenv func(string) string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var err error
+		interpolated := envVariablePattern.ReplaceAllStringFunc(v, func(reference string) string {
+			name := envVariablePattern.FindStringSubmatch(reference)[1]
+			envValue := getenv(name)
+			if envValue == "" && err == nil {
+				err = errors.Errorf("environment variable %s is not set", name)
+			}
+			return envValue
+		})
+		return interpolated, err
+	case []interface{}:
+		for key, item := range v {
+			interpolated, err := interpolate(item, getenv)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = interpolated
+		}
+		return v, nil
+	case map[string]interface{}:
+		for key, item := range v {
+			interpolated, err := interpolate(item, getenv)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = interpolated
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
 func validateConfig(config Config) error {
 	if !compliant.IsSupportedSpecVersion(config.SpecVersion) {
 		return errors.New("missing or invalid config property Specification version `spec_version`")
@@ -3,8 +3,10 @@ package main
 import (
 	"bufio"
 	"crypto/rsa"
+	"crypto/tls"
 	"flag"
 	"fmt"
+	"io"
 	http2 "net/http"
 	"os"
 	"strings"
@@ -12,8 +14,11 @@ import (
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/jwks"
 	ver "github.com/OpenBankingUK/conformance-dcr/pkg/version"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
 )
 
 func main() {
@@ -59,20 +64,192 @@ func runCmd(flags flags) {
 		os.Exit(1)
 	}
 
-	cfg, err := LoadConfig(flags.configFilePath)
+	profiles, err := selectedProfiles(flags)
+	exitOnError(err)
+	if len(profiles) > 1 {
+		runBatchCmd(flags, profiles)
+		return
+	}
+
+	profile := ""
+	if len(profiles) == 1 {
+		profile = profiles[0]
+	}
+	cfg, err := LoadConfig(flags.configFilePath, profile)
 	exitOnError(err)
 
-	client := &http2.Client{Timeout: time.Second * 5}
-	openIDConfig, err := openid.Get(cfg.WellknownEndpoint, client)
+	keyLog, err := openKeyLog(flags.tlsKeyLog)
 	exitOnError(err)
+	if keyLog != nil {
+		defer keyLog.Close()
+	}
+
+	manifest, closeJwksServer, err := newManifest(cfg, flags, keyLog)
+	exitOnError(err)
+	defer closeJwksServer()
+
+	printer := compliant.NewPrinter(flags.debug)
+	listeners := []compliant.ListenerFunc{redactedListener(flags, printer.Print)}
+
+	doneSignal := make(chan bool)
+	serverAddr := serverAddress(flags.httpServerPort)
+	if flags.report {
+		reporterFunc := compliant.NewReporter(runConfig(cfg), flags.debug, doneSignal, serverAddr)
+		listeners = append(listeners, redactedListener(flags, reporterFunc.Report))
+	}
+
+	passes, err := runManifest(manifest, listeners...)
+	exitOnError(err)
+
+	if flags.report {
+		waitForDownloadOrTimeout(serverAddr, doneSignal)
+	}
+
+	if !passes {
+		os.Exit(1)
+	}
+}
+
+// runBatchCmd runs the manifest for each profile, ie: each brand of an ASPSP group,
+// then prints the scenario by profile matrix
+func runBatchCmd(flags flags, profiles []string) {
+	keyLog, err := openKeyLog(flags.tlsKeyLog)
+	exitOnError(err)
+	if keyLog != nil {
+		defer keyLog.Close()
+	}
+
+	printer := compliant.NewPrinter(flags.debug)
+	var results compliant.TargetResults
+	for _, profile := range profiles {
+		fmt.Printf("=== Profile: %s\n", profile)
+		results = append(results, runTarget(flags, profile, keyLog, redactedListener(flags, printer.Print)))
+	}
+	if !flags.noRedact {
+		results = results.Redacted()
+	}
+
+	fmt.Println("=== Scenario by profile matrix")
+	exitOnError(compliant.NewMatrix(results).Print(os.Stdout))
+
+	if flags.report {
+		doneSignal := make(chan bool)
+		serverAddr := serverAddress(flags.httpServerPort)
+		err := compliant.NewBatchReporter(flags.debug, doneSignal, serverAddr).Report(results)
+		exitOnError(err)
+		waitForDownloadOrTimeout(serverAddr, doneSignal)
+	}
+
+	if results.Fail() {
+		os.Exit(1)
+	}
+}
+
+// runTarget runs the manifest for a profile, a profile that can't run is recorded as an error
+// so the remaining profiles of the batch still run
+func runTarget(flags flags, profile string, keyLog io.Writer, printer compliant.ListenerFunc) compliant.TargetResult {
+	target := compliant.TargetResult{Name: profile}
+
+	cfg, err := LoadConfig(flags.configFilePath, profile)
+	if err != nil {
+		fmt.Println(err.Error())
+		target.Error = err.Error()
+		return target
+	}
+	target.Config = runConfig(cfg)
+
+	manifest, closeJwksServer, err := newManifest(cfg, flags, keyLog)
+	if err != nil {
+		fmt.Println(err.Error())
+		target.Error = err.Error()
+		return target
+	}
+	defer closeJwksServer()
+
+	recordResult := func(result compliant.ManifestResult) error {
+		target.Result = result
+		return nil
+	}
+	if _, err = runManifest(manifest, recordResult, printer); err != nil {
+		fmt.Println(err.Error())
+	}
+	return target
+}
+
+// runManifest runs the manifest with a tester notifying the listeners of the result, in order
+func runManifest(manifest compliant.Manifest, listeners ...compliant.ListenerFunc) (bool, error) {
+	tester := compliant.NewTester()
+	for _, listener := range listeners {
+		tester.AddListener(listener)
+	}
+	return tester.Compliant(manifest)
+}
+
+// redactedListener masks secrets in the results passed to a printer or reporter, unless disabled with `-no-redact`
+func redactedListener(flags flags, listener compliant.ListenerFunc) compliant.ListenerFunc {
+	if flags.noRedact {
+		return listener
+	}
+	return compliant.NewRedactedListener(listener)
+}
+
+// selectedProfiles are the comma separated `-profile` values or all the config profiles with `-all-profiles`
+func selectedProfiles(flags flags) ([]string, error) {
+	if flags.allProfiles {
+		profiles, err := LoadProfileNames(flags.configFilePath)
+		if err != nil {
+			return nil, err
+		}
+		if len(profiles) == 0 {
+			return nil, errors.New("-all-profiles: config doesn't define profiles")
+		}
+		return profiles, nil
+	}
+
+	var profiles []string
+	for _, profile := range strings.Split(flags.profile, ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+// newManifest builds the manifest to run against the config ASPSP, the returned func closes the jwks server if started
+// TLS session keys are written to keyLog when not nil
+func newManifest(cfg Config, flags flags, keyLog io.Writer) (compliant.Manifest, func(), error) {
+	noop := func() {}
+
+	var recorder *http.Recorder
+	if flags.har {
+		recorder = http.NewRecorder()
+	}
+
+	network := networkConfig(cfg)
+	discoveryTransport := http2.DefaultTransport.(*http2.Transport).Clone()
+	if keyLog != nil {
+		discoveryTransport.TLSClientConfig = &tls.Config{KeyLogWriter: keyLog}
+	}
+	transport, err := network.Transport(discoveryTransport)
+	if err != nil {
+		return nil, noop, err
+	}
+	client := &http2.Client{Transport: transport, Timeout: time.Second * 5}
+	openIDConfig, err := discover(cfg, client)
+	if err != nil {
+		return nil, noop, err
+	}
 
 	dcr32Cfg, err := compliant.NewDCR32Config(
 		openIDConfig,
+		cfg.DiscoveryDocument != "" || len(cfg.DiscoveryOverrides) > 0,
+		cfg.WellknownEndpoint,
 		cfg.SSA,
 		cfg.Aud,
 		cfg.Kid,
 		cfg.Issuer,
 		cfg.RedirectURIs,
+		cfg.Scopes,
 		cfg.SigningKeyPEM,
 		cfg.TransportKeyPEM,
 		cfg.TransportCertPEM,
@@ -82,50 +259,145 @@ func runCmd(flags flags) {
 		cfg.PutImplemented,
 		cfg.DeleteImplemented,
 		flags.tlsSkipVerify,
+		network,
+		recorder,
+		keyLog,
+		cfg.ServerCertExpiryDays,
 		cfg.SpecVersion,
 	)
-	exitOnError(err)
+	if err != nil {
+		return nil, noop, err
+	}
+
+	closeJwksServer := noop
+	if flags.jwksPort != "" {
+		var jwksServer *jwks.Server
+		jwksServer, err = startJwksServer(dcr32Cfg, flags.jwksPort, flags.jwksTLSCert, flags.jwksTLSKey)
+		if err != nil {
+			return nil, noop, err
+		}
+		closeJwksServer = func() {
+			if err := jwksServer.Close(); err != nil {
+				fmt.Printf("WARN %s\n", err)
+			}
+		}
+	}
 
 	manifest, err := compliant.NewSpecManifest(cfg.SpecVersion, dcr32Cfg)
-	exitOnError(err)
+	if err != nil {
+		closeJwksServer()
+		return nil, noop, err
+	}
+
+	if flags.allAuthMethods {
+		manifest, err = compliant.NewAuthMethodsManifest(manifest, dcr32Cfg)
+		if err != nil {
+			closeJwksServer()
+			return nil, noop, err
+		}
+	}
 
 	if flags.filterExpression != "" {
 		manifest, err = compliant.NewFilteredManifest(manifest, flags.filterExpression)
-		exitOnError(err)
+		if err != nil {
+			closeJwksServer()
+			return nil, noop, err
+		}
 	}
 
-	tester := compliant.NewTester()
+	if recorder != nil {
+		manifest, err = compliant.NewRecordedManifest(manifest, recorder)
+		if err != nil {
+			closeJwksServer()
+			return nil, noop, err
+		}
+	}
 
-	printer := compliant.NewPrinter(flags.debug)
-	tester.AddListener(printer.Print)
+	return manifest, closeJwksServer, nil
+}
 
-	doneSignal := make(chan bool)
-	serverAddr := serverAddress(flags.httpServerPort)
-	if flags.report {
-		reporterFunc := compliant.NewReporter(runConfig(cfg), flags.debug, doneSignal, serverAddr)
-		tester.AddListener(reporterFunc.Report)
+// openKeyLog opens the `-tls-keylog` file to append the TLS session keys to, nil when not set
+func openKeyLog(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nil, nil
+	}
+	keyLog, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening tls key log")
 	}
+	fmt.Printf("WARN TLS session keys are written to %s, anyone with the file can decrypt the captured traffic\n", path)
+	return keyLog, nil
+}
 
-	passes, err := tester.Compliant(manifest)
-	exitOnError(err)
+func networkConfig(cfg Config) http.NetworkConfig {
+	return http.NetworkConfig{
+		ProxyURL: cfg.ProxyURL,
+		NoProxy:  cfg.NoProxy,
+		Headers:  cfg.Headers,
+		Hosts:    cfg.Hosts,
+	}
+}
 
-	if flags.report {
-		waitForDownloadOrTimeout(serverAddr, doneSignal)
+// discover fetches the well-known discovery document, or reads it from the config `discovery_document` file,
+// then applies the config `discovery_overrides`
+func discover(cfg Config, client *http2.Client) (openid.Configuration, error) {
+	var openIDConfig openid.Configuration
+	var err error
+	if cfg.DiscoveryDocument != "" {
+		fmt.Printf("WARN discovery document read from %s\n", cfg.DiscoveryDocument)
+		openIDConfig, err = openid.Load(cfg.DiscoveryDocument)
+	} else {
+		openIDConfig, err = openid.Get(cfg.WellknownEndpoint, client)
+	}
+	if err != nil {
+		return openid.Configuration{}, err
 	}
 
-	if !passes {
-		os.Exit(1)
+	if len(cfg.DiscoveryOverrides) > 0 {
+		fmt.Printf("WARN discovery metadata overridden: %s\n", strings.Join(discoveryOverrideNames(cfg), ", "))
 	}
+	return openid.Override(openIDConfig, cfg.DiscoveryOverrides)
+}
+
+// startJwksServer publishes the signing key and transport certificate for ASPSPs fetching them from a jwks uri,
+// served over https when a server certificate and key files are set
+func startJwksServer(cfg compliant.DCR32Config, port, certFile, keyFile string) (*jwks.Server, error) {
+	set, err := jwks.NewTPPSet(cfg.PrivateKey, cfg.KID, cfg.TransportCert)
+	if err != nil {
+		return nil, err
+	}
+
+	server := jwks.NewServer(serverAddress(port), set)
+	switch {
+	case certFile == "" && keyFile == "":
+		err = server.Start()
+	case certFile == "" || keyFile == "":
+		return nil, errors.New("jwks server needs both -jwks-tls-cert and -jwks-tls-key to serve https")
+	default:
+		var cert tls.Certificate
+		cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading jwks server certificate")
+		}
+		err = server.StartTLS(cert)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Serving jwks on %s\n", server.URL())
+	return server, nil
 }
 
 func runConfig(config Config) compliant.RunConfig {
 	return compliant.RunConfig{
-		WellknownEndpoint: config.WellknownEndpoint,
-		GetImplemented:    config.GetImplemented,
-		PutImplemented:    config.PutImplemented,
-		DeleteImplemented: config.DeleteImplemented,
-		Environment:       config.Environment,
-		Brand:             config.Brand,
+		WellknownEndpoint:  config.WellknownEndpoint,
+		GetImplemented:     config.GetImplemented,
+		PutImplemented:     config.PutImplemented,
+		DeleteImplemented:  config.DeleteImplemented,
+		Environment:        config.Environment,
+		Brand:              config.Brand,
+		DiscoveryDocument:  config.DiscoveryDocument,
+		DiscoveryOverrides: discoveryOverrideNames(config),
 	}
 }
 
@@ -149,33 +421,85 @@ func serverAddress(port string) string {
 type flags struct {
 	versionCmd       bool
 	configFilePath   string
+	profile          string
+	allProfiles      bool
 	filterExpression string
+	allAuthMethods   bool
 	debug            bool
 	report           bool
+	har              bool
+	noRedact         bool
 	tlsSkipVerify    bool
 	httpServerPort   string
+	jwksPort         string
+	jwksTLSCert      string
+	jwksTLSKey       string
+	tlsKeyLog        string
 }
 
 func mustParseFlags() flags {
-	var configFilePath, filterExpression, httpServerPort string
-	var debug, report, versionFlag, tlsSkipVerify bool
-	flag.StringVar(&configFilePath, "config-path", "", "Config file path")
+	var configFilePath, profile, filterExpression, httpServerPort, jwksPort, jwksTLSCert, jwksTLSKey, tlsKeyLog string
+	var debug, report, har, noRedact, versionFlag, tlsSkipVerify, allAuthMethods, allProfiles bool
+	flag.StringVar(&configFilePath, "config-path", "", "Config file path, JSON or YAML (.yaml/.yml)")
+	flag.StringVar(
+		&profile,
+		"profile",
+		"",
+		"Config profile to run when the config file defines profiles, comma separated profiles run as a batch",
+	)
+	flag.BoolVar(&allProfiles, "all-profiles", false, "Run all the config file profiles as a batch")
 	flag.StringVar(&filterExpression, "filter", "", "Filter scenarios containing value")
+	flag.BoolVar(
+		&allAuthMethods,
+		"all-auth-methods",
+		false,
+		"Run registration scenarios for each supported token endpoint auth method",
+	)
 	flag.StringVar(&httpServerPort, "port", "8080", "Http server port for report download")
+	flag.StringVar(
+		&jwksPort,
+		"jwks-port",
+		"",
+		"Serve the signing and transport keys jwks on this port, disabled by default",
+	)
+	flag.StringVar(&jwksTLSCert, "jwks-tls-cert", "", "PEM server certificate file to serve the jwks over https")
+	flag.StringVar(&jwksTLSKey, "jwks-tls-key", "", "PEM server private key file to serve the jwks over https")
 	flag.BoolVar(&debug, "debug", false, "Enable debug defaults to disabled")
 	flag.BoolVar(&report, "report", false, "Enable report output defaults to disabled")
+	flag.BoolVar(&har, "har", false, "Add to the report a HAR file of the http traffic of each scenario")
+	flag.BoolVar(
+		&noRedact,
+		"no-redact",
+		false,
+		"Disable the masking of secrets and tokens in debug output and reports, for local debugging only",
+	)
 	flag.BoolVar(&versionFlag, "version", false, "Print the version details of conformance-dcr")
 	flag.BoolVar(&tlsSkipVerify, "tlsskipverify", false, "Skip ssl cert verify")
+	flag.StringVar(
+		&tlsKeyLog,
+		"tls-keylog",
+		"",
+		"Append the TLS session keys to this file in NSS key log format, to decrypt captured traffic in Wireshark",
+	)
 	flag.Parse()
 
 	return flags{
 		configFilePath:   configFilePath,
+		profile:          profile,
+		allProfiles:      allProfiles,
 		filterExpression: filterExpression,
+		allAuthMethods:   allAuthMethods,
 		debug:            debug,
 		report:           report,
+		har:              har,
+		noRedact:         noRedact,
 		versionCmd:       versionFlag,
 		tlsSkipVerify:    tlsSkipVerify,
 		httpServerPort:   httpServerPort,
+		jwksPort:         jwksPort,
+		jwksTLSCert:      jwksTLSCert,
+		jwksTLSKey:       jwksTLSKey,
+		tlsKeyLog:        tlsKeyLog,
 	}
 }
 
@@ -0,0 +1,97 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// DecryptPEM returns the unencrypted PEM of a password protected private key,
+// supports PKCS#8 `ENCRYPTED PRIVATE KEY` and legacy OpenSSL `Proc-Type: 4,ENCRYPTED` PEMs.
+// Keys that are not encrypted are returned unchanged.
+func DecryptPEM(keyPEM []byte, password string) ([]byte, error) {
+	block := privateKeyBlock(keyPEM)
+	if block == nil {
+		return keyPEM, nil
+	}
+
+	// nolint:staticcheck
+	encrypted := block.Type == "ENCRYPTED PRIVATE KEY" || x509.IsEncryptedPEMBlock(block)
+	if !encrypted {
+		return keyPEM, nil
+	}
+	if password == "" {
+		return nil, errors.New("decrypting private key: key is encrypted and no password was provided")
+	}
+
+	if block.Type == "ENCRYPTED PRIVATE KEY" {
+		key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(password))
+		if err != nil {
+			return nil, errors.Wrap(err, "decrypting private key")
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "decrypting private key")
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	}
+
+	// nolint:staticcheck
+	der, err := x509.DecryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+}
+
+// ParsePKCS12 returns the PEM encoded certificate chain and private key of a .p12/.pfx bundle
+func ParsePKCS12(data []byte, password string) (certPEM, keyPEM string, err error) {
+	key, cert, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return "", "", errors.Wrap(err, "parsing pkcs12 bundle")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", errors.Wrap(err, "parsing pkcs12 bundle")
+	}
+
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	for _, caCert := range caCerts {
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})...)
+	}
+
+	return string(chain), string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// privateKeyBlock returns the first private key block, skipping blocks like `EC PARAMETERS`
+func privateKeyBlock(keyPEM []byte) *pem.Block {
+	for {
+		var block *pem.Block
+		block, keyPEM = pem.Decode(keyPEM)
+		if block == nil {
+			return nil
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			return block
+		}
+	}
+}
+
+// parsePrivateKey parses PKCS#1 RSA, PKCS#8 and SEC1 EC private keys
+func parsePrivateKey(der []byte) (interface{}, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unknown private key format")
+}
@@ -0,0 +1,158 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestParseSigningKeyFromPEM_KeyFormats(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pkcs8RSA, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	pkcs8EC, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	sec1EC, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	blocks := []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		{Type: "PRIVATE KEY", Bytes: pkcs8RSA},
+		{Type: "PRIVATE KEY", Bytes: pkcs8EC},
+		{Type: "EC PRIVATE KEY", Bytes: sec1EC},
+	}
+	for _, block := range blocks {
+		key, err := ParseSigningKeyFromPEM(pem.EncodeToMemory(block))
+
+		assert.NoError(t, err)
+		assert.NotNil(t, key)
+	}
+}
+
+func TestParseSigningKeyFromPEM_SkipsECParameters(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sec1EC, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	keyPEM := append(
+		pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte("params")}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1EC})...,
+	)
+
+	key, err := ParseSigningKeyFromPEM(keyPEM)
+
+	require.NoError(t, err)
+	assert.Equal(t, ecKey.D, key.(*ecdsa.PrivateKey).D)
+}
+
+func TestParseSigningKeyFromPEM_FailsOnUnsupportedCurve(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	sec1EC, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	_, err = ParseSigningKeyFromPEM(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1EC}))
+
+	assert.EqualError(t, err, "parsing signing key: unsupported EC curve P-384")
+}
+
+func TestDecryptPEM_EncryptedPKCS8(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := pkcs8.ConvertPrivateKeyToPKCS8(ecKey, []byte("password"))
+	require.NoError(t, err)
+	encrypted := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der})
+
+	decrypted, err := DecryptPEM(encrypted, "password")
+	require.NoError(t, err)
+
+	key, err := ParseSigningKeyFromPEM(decrypted)
+	require.NoError(t, err)
+	assert.Equal(t, ecKey.D, key.(*ecdsa.PrivateKey).D)
+}
+
+func TestDecryptPEM_LegacyEncryptedPEM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	// nolint:staticcheck
+	block, err := x509.EncryptPEMBlock(
+		rand.Reader,
+		"RSA PRIVATE KEY",
+		x509.MarshalPKCS1PrivateKey(rsaKey),
+		[]byte("password"),
+		x509.PEMCipherAES256,
+	)
+	require.NoError(t, err)
+
+	decrypted, err := DecryptPEM(pem.EncodeToMemory(block), "password")
+	require.NoError(t, err)
+
+	key, err := ParseSigningKeyFromPEM(decrypted)
+	require.NoError(t, err)
+	assert.Equal(t, rsaKey.D, key.(*rsa.PrivateKey).D)
+}
+
+func TestDecryptPEM_FailsWithoutPassword(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := pkcs8.ConvertPrivateKeyToPKCS8(ecKey, []byte("password"))
+	require.NoError(t, err)
+
+	_, err = DecryptPEM(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der}), "")
+
+	assert.EqualError(t, err, "decrypting private key: key is encrypted and no password was provided")
+}
+
+func TestDecryptPEM_ReturnsUnencryptedKey(t *testing.T) {
+	selfSigned, err := NewSelfSigned(pkix.Name{CommonName: "tpp"}, time.Hour)
+	require.NoError(t, err)
+
+	keyPEM, err := DecryptPEM([]byte(selfSigned.KeyPEM), "")
+
+	require.NoError(t, err)
+	assert.Equal(t, selfSigned.KeyPEM, string(keyPEM))
+}
+
+func TestParsePKCS12(t *testing.T) {
+	selfSigned, err := NewSelfSigned(pkix.Name{CommonName: "tpp"}, time.Hour)
+	require.NoError(t, err)
+	key, err := ParseSigningKeyFromPEM([]byte(selfSigned.KeyPEM))
+	require.NoError(t, err)
+	bundle, err := pkcs12.Modern.Encode(key, selfSigned.Cert, nil, "password")
+	require.NoError(t, err)
+
+	certPEM, keyPEM, err := ParsePKCS12(bundle, "password")
+	require.NoError(t, err)
+
+	assert.Equal(t, selfSigned.CertPEM, certPEM)
+	_, err = tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	assert.NoError(t, err)
+}
+
+func TestParsePKCS12_FailsOnWrongPassword(t *testing.T) {
+	selfSigned, err := NewSelfSigned(pkix.Name{CommonName: "tpp"}, time.Hour)
+	require.NoError(t, err)
+	key, err := ParseSigningKeyFromPEM([]byte(selfSigned.KeyPEM))
+	require.NoError(t, err)
+	bundle, err := pkcs12.Modern.Encode(key, selfSigned.Cert, nil, "password")
+	require.NoError(t, err)
+
+	_, _, err = ParsePKCS12(bundle, "wrong")
+
+	assert.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package certs
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/pkg/errors"
@@ -25,3 +28,29 @@ func ParseRsaPrivateKeyFromPemFile(privFile string) (*rsa.PrivateKey, error) {
 
 	return privateKey, nil
 }
+
+// ParseSigningKeyFromPEM parses a RSA or ECDSA P-256 private key used to sign JWTs,
+// in PKCS#1, PKCS#8 or SEC1 format. Encrypted keys must be decrypted with DecryptPEM first.
+func ParseSigningKeyFromPEM(keyPEM []byte) (crypto.Signer, error) {
+	block := privateKeyBlock(keyPEM)
+	if block == nil {
+		return nil, errors.New("parsing signing key: key must be a PEM encoded RSA or EC private key")
+	}
+
+	key, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.New("parsing signing key: key must be a PEM encoded RSA or EC private key")
+	}
+
+	switch signingKey := key.(type) {
+	case *rsa.PrivateKey:
+		return signingKey, nil
+	case *ecdsa.PrivateKey:
+		if signingKey.Curve != elliptic.P256() {
+			return nil, errors.Errorf("parsing signing key: unsupported EC curve %s", signingKey.Curve.Params().Name)
+		}
+		return signingKey, nil
+	}
+
+	return nil, errors.Errorf("parsing signing key: unsupported key type %T", key)
+}
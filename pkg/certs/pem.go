@@ -0,0 +1,79 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const envReferencePrefix = "env:"
+
+// ResolvePEM returns the PEM of a config value that is either an inline PEM,
+// an `env:NAME` reference to an environment variable holding the PEM or a path to a PEM file.
+// Relative paths are resolved from baseDir.
+func ResolvePEM(value, baseDir string, getenv func(string) string) (string, error) {
+	switch {
+	case value == "" || strings.Contains(value, "-----BEGIN"):
+		return value, nil
+	case strings.HasPrefix(value, envReferencePrefix):
+		name := strings.TrimPrefix(value, envReferencePrefix)
+		pemValue := getenv(name)
+		if pemValue == "" {
+			return "", errors.Errorf("environment variable %s is not set", name)
+		}
+		return pemValue, nil
+	}
+
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "reading pem file")
+	}
+	return string(content), nil
+}
+
+// ParseCertificateChain parses all the certificates of a PEM encoded chain, leaf first
+func ParseCertificateChain(chainPEM []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, chainPEM = pem.Decode(chainPEM)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing certificate chain")
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("parsing certificate chain: no PEM encoded certificate found")
+	}
+	return chain, nil
+}
+
+// SplitCertificateChain returns each certificate of a PEM encoded chain as its own PEM
+func SplitCertificateChain(chainPEM []byte) ([]string, error) {
+	chain, err := ParseCertificateChain(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	certsPEM := make([]string, len(chain))
+	for key, cert := range chain {
+		certsPEM[key] = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+	return certsPEM, nil
+}
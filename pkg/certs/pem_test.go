@@ -0,0 +1,74 @@
+package certs
+
+import (
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePEM_Inline(t *testing.T) {
+	value := "-----BEGIN CERTIFICATE-----\nMIIF\n-----END CERTIFICATE-----\n"
+
+	resolved, err := ResolvePEM(value, "", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, value, resolved)
+}
+
+func TestResolvePEM_EnvReference(t *testing.T) {
+	getenv := func(name string) string {
+		assert.Equal(t, "TRANSPORT_CERT", name)
+		return "cert from env"
+	}
+
+	resolved, err := ResolvePEM("env:TRANSPORT_CERT", "", getenv)
+
+	require.NoError(t, err)
+	assert.Equal(t, "cert from env", resolved)
+}
+
+func TestResolvePEM_FailsOnMissingEnvVariable(t *testing.T) {
+	_, err := ResolvePEM("env:TRANSPORT_CERT", "", func(string) string { return "" })
+
+	assert.EqualError(t, err, "environment variable TRANSPORT_CERT is not set")
+}
+
+func TestResolvePEM_RelativeFilePath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "key.pem"), []byte("key from file"), 0600))
+
+	resolved, err := ResolvePEM("key.pem", dir, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "key from file", resolved)
+}
+
+func TestResolvePEM_FailsOnMissingFile(t *testing.T) {
+	_, err := ResolvePEM("/does/not/exist.pem", "", nil)
+
+	assert.EqualError(t, err, "reading pem file: open /does/not/exist.pem: no such file or directory")
+}
+
+func TestSplitCertificateChain(t *testing.T) {
+	leaf, err := NewSelfSigned(pkix.Name{CommonName: "leaf"}, time.Hour)
+	require.NoError(t, err)
+	root, err := NewSelfSigned(pkix.Name{CommonName: "root"}, time.Hour)
+	require.NoError(t, err)
+	chainPEM := leaf.CertPEM + "\n" + root.CertPEM
+
+	certsPEM, err := SplitCertificateChain([]byte(chainPEM))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{leaf.CertPEM, root.CertPEM}, certsPEM)
+}
+
+func TestParseCertificateChain_FailsWithoutCertificate(t *testing.T) {
+	_, err := ParseCertificateChain([]byte("not a pem"))
+
+	assert.EqualError(t, err, "parsing certificate chain: no PEM encoded certificate found")
+}
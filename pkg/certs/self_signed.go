@@ -0,0 +1,129 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SelfSigned certificate and key in PEM format, as expected by the mTLS http client builder.
+// CertPEM holds the certificate chain when the certificate is issued by a generated CA.
+type SelfSigned struct {
+	Cert    *x509.Certificate
+	CertPEM string
+	KeyPEM  string
+}
+
+// NewSelfSigned generates an EC P-256 self signed client certificate for the subject,
+// used by the self_signed_tls_client_auth token endpoint auth method
+func NewSelfSigned(subject pkix.Name, validity time.Duration) (SelfSigned, error) {
+	now := time.Now()
+	return newClientCert(subject, now.Add(-time.Minute), now.Add(validity))
+}
+
+// NewExpiredSelfSigned generates a self signed client certificate for the subject that expired a day ago,
+// ASPSPs must refuse it as a transport certificate, being self signed it is also untrusted
+func NewExpiredSelfSigned(subject pkix.Name) (SelfSigned, error) {
+	now := time.Now()
+	return newClientCert(subject, now.AddDate(0, 0, -2), now.AddDate(0, 0, -1))
+}
+
+// NewUntrustedCASigned generates a client certificate for the subject issued by a CA generated on the fly,
+// which no ASPSP trusts
+func NewUntrustedCASigned(subject pkix.Name, validity time.Duration) (SelfSigned, error) {
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "generating untrusted CA key")
+	}
+	caSubject := pkix.Name{CommonName: "conformance-dcr untrusted CA"}
+	caTemplate, err := certTemplate(caSubject, now.Add(-time.Minute), now.Add(validity))
+	if err != nil {
+		return SelfSigned{}, err
+	}
+	caTemplate.IsCA = true
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign
+	caTemplate.ExtKeyUsage = nil
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "creating untrusted CA certificate")
+	}
+	ca, err := x509.ParseCertificate(caDer)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "creating untrusted CA certificate")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "generating client certificate key")
+	}
+	template, err := certTemplate(subject, now.Add(-time.Minute), now.Add(validity))
+	if err != nil {
+		return SelfSigned{}, err
+	}
+	clientCert, err := encodeClientCert(template, ca, key, caKey)
+	if err != nil {
+		return SelfSigned{}, err
+	}
+	clientCert.CertPEM += string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer}))
+	return clientCert, nil
+}
+
+func newClientCert(subject pkix.Name, notBefore, notAfter time.Time) (SelfSigned, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "generating self signed certificate key")
+	}
+	template, err := certTemplate(subject, notBefore, notAfter)
+	if err != nil {
+		return SelfSigned{}, err
+	}
+	return encodeClientCert(template, template, key, key)
+}
+
+func certTemplate(subject pkix.Name, notBefore, notAfter time.Time) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "generating self signed certificate serial number")
+	}
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, nil
+}
+
+// encodeClientCert creates the certificate of the template for the key, signed by the parent key
+func encodeClientCert(template, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) (SelfSigned, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "creating self signed certificate")
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "creating self signed certificate")
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return SelfSigned{}, errors.Wrap(err, "encoding self signed certificate key")
+	}
+
+	return SelfSigned{
+		Cert:    cert,
+		CertPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		KeyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}, nil
+}
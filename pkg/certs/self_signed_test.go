@@ -0,0 +1,51 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSelfSigned(t *testing.T) {
+	selfSigned, err := NewSelfSigned(pkix.Name{CommonName: "tpp"}, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, "tpp", selfSigned.Cert.Subject.CommonName)
+	assert.Equal(t, selfSigned.Cert.Subject.String(), selfSigned.Cert.Issuer.String())
+	cert := selfSigned.Cert
+	assert.NoError(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature))
+
+	_, err = tls.X509KeyPair([]byte(selfSigned.CertPEM), []byte(selfSigned.KeyPEM))
+	assert.NoError(t, err)
+}
+
+func TestNewExpiredSelfSigned(t *testing.T) {
+	expired, err := NewExpiredSelfSigned(pkix.Name{CommonName: "tpp"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "tpp", expired.Cert.Subject.CommonName)
+	assert.True(t, expired.Cert.NotAfter.Before(time.Now()))
+
+	_, err = tls.X509KeyPair([]byte(expired.CertPEM), []byte(expired.KeyPEM))
+	assert.NoError(t, err)
+}
+
+func TestNewUntrustedCASigned(t *testing.T) {
+	untrusted, err := NewUntrustedCASigned(pkix.Name{CommonName: "tpp"}, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, "tpp", untrusted.Cert.Subject.CommonName)
+	assert.Equal(t, "CN=conformance-dcr untrusted CA", untrusted.Cert.Issuer.String())
+
+	keyPair, err := tls.X509KeyPair([]byte(untrusted.CertPEM), []byte(untrusted.KeyPEM))
+	require.NoError(t, err)
+	require.Len(t, keyPair.Certificate, 2)
+	ca, err := x509.ParseCertificate(keyPair.Certificate[1])
+	require.NoError(t, err)
+	assert.NoError(t, untrusted.Cert.CheckSignatureFrom(ca))
+}
@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"time"
 
@@ -17,12 +17,67 @@ type Authoriser interface {
 	Client(response []byte) (client.Client, error)
 }
 
+// supportedAuthMethods token endpoint auth methods in order of preference
+var supportedAuthMethods = []string{
+	"tls_client_auth",
+	"private_key_jwt",
+	"client_secret_jwt",
+	"client_secret_basic",
+	"client_secret_post",
+	"self_signed_tls_client_auth",
+}
+
+// SupportedAuthMethods returns the token endpoint auth methods advertised by the ASPSP that can be tested,
+// in order of preference
+func SupportedAuthMethods(config openid.Configuration) []string {
+	var methods []string
+	for _, method := range supportedAuthMethods {
+		if sliceContains(method, config.TokenEndpointAuthMethodsSupported) {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// NewAuthoriser creates an authoriser for the preferred token endpoint auth method advertised by the ASPSP
 func NewAuthoriser(
 	config openid.Configuration,
 	ssa, aud, kid, issuer string, tokenEndpointSignMethod jwt.SigningMethod,
 	redirectURIs []string,
 	responseTypes []string,
-	privateKey *rsa.PrivateKey,
+	scope string,
+	privateKey crypto.Signer,
+	jwtExpiration time.Duration,
+	transportCert *x509.Certificate,
+	transportSubjectDn string,
+) Authoriser {
+	methods := SupportedAuthMethods(config)
+	if len(methods) == 0 {
+		return none{}
+	}
+	return NewAuthoriserForMethod(
+		methods[0],
+		config,
+		ssa, aud, kid, issuer, tokenEndpointSignMethod,
+		redirectURIs,
+		responseTypes,
+		scope,
+		privateKey,
+		jwtExpiration,
+		transportCert,
+		transportSubjectDn,
+	)
+}
+
+// NewAuthoriserForMethod creates an authoriser for a given token endpoint auth method
+func NewAuthoriserForMethod(
+	authMethod string,
+	config openid.Configuration,
+	ssa, aud, kid, issuer string, tokenEndpointSignMethod jwt.SigningMethod,
+	redirectURIs []string,
+	responseTypes []string,
+	scope string,
+	privateKey crypto.Signer,
 	jwtExpiration time.Duration,
 	transportCert *x509.Certificate,
 	transportSubjectDn string,
@@ -32,91 +87,49 @@ func NewAuthoriser(
 		requestObjectSignAlg = config.RequestObjectSignAlgSupported[0]
 	}
 
-	if sliceContains("tls_client_auth", config.TokenEndpointAuthMethodsSupported) {
-		return NewTlsClientAuth(
-			config.TokenEndpoint,
-			NewJwtSigner(
-				tokenEndpointSignMethod,
-				ssa,
-				issuer,
-				aud,
-				kid,
-				"tls_client_auth",
-				requestObjectSignAlg,
-				redirectURIs,
-				responseTypes,
-				privateKey,
-				jwtExpiration,
-				transportCert,
-				transportSubjectDn,
-			),
-		)
-	}
-	if sliceContains("private_key_jwt", config.TokenEndpointAuthMethodsSupported) {
-		return NewClientPrivateKeyJwt(
-			config.TokenEndpoint,
-			tokenEndpointSignMethod,
-			privateKey,
-			NewJwtSigner(
-				tokenEndpointSignMethod,
-				ssa,
-				issuer,
-				aud,
-				kid,
-				"private_key_jwt",
-				requestObjectSignAlg,
-				redirectURIs,
-				responseTypes,
-				privateKey,
-				jwtExpiration,
-				transportCert,
-				transportSubjectDn,
-			),
-		)
-	}
-	if sliceContains("client_secret_jwt", config.TokenEndpointAuthMethodsSupported) {
-		return NewClientSecretJWT(
-			config.TokenEndpoint,
-			NewJwtSigner(
-				tokenEndpointSignMethod,
-				ssa,
-				issuer,
-				aud,
-				kid,
-				"client_secret_jwt",
-				requestObjectSignAlg,
-				redirectURIs,
-				responseTypes,
-				privateKey,
-				jwtExpiration,
-				transportCert,
-				transportSubjectDn,
-			),
-		)
-	}
-	if sliceContains("client_secret_basic", config.TokenEndpointAuthMethodsSupported) {
-		return NewClientSecretBasic(
-			config.TokenEndpoint,
-			NewJwtSigner(
-				tokenEndpointSignMethod,
-				ssa,
-				issuer,
-				aud,
-				kid,
-				"client_secret_basic",
-				requestObjectSignAlg,
-				redirectURIs,
-				responseTypes,
-				privateKey,
-				jwtExpiration,
-				transportCert,
-				transportSubjectDn,
-			),
-		)
+	signer := NewJwtSigner(
+		tokenEndpointSignMethod,
+		ssa,
+		issuer,
+		aud,
+		kid,
+		authMethod,
+		requestObjectSignAlg,
+		idTokenSignAlg(config, tokenEndpointSignMethod),
+		redirectURIs,
+		responseTypes,
+		scope,
+		privateKey,
+		jwtExpiration,
+		transportCert,
+		transportSubjectDn,
+	)
+
+	switch authMethod {
+	case "tls_client_auth", "self_signed_tls_client_auth":
+		return NewTlsClientAuth(config.TokenEndpoint, signer)
+	case "private_key_jwt":
+		return NewClientPrivateKeyJwt(config.TokenEndpoint, tokenEndpointSignMethod, privateKey, signer)
+	case "client_secret_jwt":
+		return NewClientSecretJWT(config.TokenEndpoint, signer)
+	case "client_secret_basic":
+		return NewClientSecretBasic(config.TokenEndpoint, signer)
+	case "client_secret_post":
+		return NewClientSecretPost(config.TokenEndpoint, signer)
 	}
 	return none{}
 }
 
+// idTokenSignAlg picks the id_token_signed_response_alg from the algorithms the ASPSP
+// advertises for id tokens, preferring the one used to sign at the token endpoint
+func idTokenSignAlg(config openid.Configuration, tokenEndpointSignMethod jwt.SigningMethod) string {
+	if len(config.IdTokenSigningAlgSupported) == 0 ||
+		sliceContains(tokenEndpointSignMethod.Alg(), config.IdTokenSigningAlgSupported) {
+		return tokenEndpointSignMethod.Alg()
+	}
+	return config.IdTokenSigningAlgSupported[0]
+}
+
 func sliceContains(value string, list []string) bool {
 	for _, item := range list {
 		if value == item {
@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"errors"
 	"time"
@@ -16,15 +16,19 @@ type AuthoriserBuilder struct {
 	tokenEndpointSignMethod jwt.SigningMethod
 	redirectURIs            []string
 	responseTypes           []string
-	privateKey              *rsa.PrivateKey
+	scope                   string
+	privateKey              crypto.Signer
 	jwtExpiration           time.Duration
 	transportCert           *x509.Certificate
 	transportCertSubjectDn  string
+	authMethod              string
+	selfSignedCert          *x509.Certificate
 }
 
 func NewAuthoriserBuilder() AuthoriserBuilder {
 	return AuthoriserBuilder{
 		jwtExpiration: time.Hour,
+		scope:         "accounts openid",
 	}
 }
 
@@ -38,6 +42,12 @@ func (b AuthoriserBuilder) WithTransportCertSubjectDn(transportSubjectDn string)
 	return b
 }
 
+// WithSelfSignedCert sets the certificate registered in the client jwks for self_signed_tls_client_auth
+func (b AuthoriserBuilder) WithSelfSignedCert(selfSignedCert *x509.Certificate) AuthoriserBuilder {
+	b.selfSignedCert = selfSignedCert
+	return b
+}
+
 func (b AuthoriserBuilder) WithOpenIDConfig(cfg openid.Configuration) AuthoriserBuilder {
 	b.config = cfg
 	return b
@@ -68,6 +78,12 @@ func (b AuthoriserBuilder) WithTokenEndpointAuthMethod(alg jwt.SigningMethod) Au
 	return b
 }
 
+// WithAuthMethod forces the token endpoint auth method instead of using the preferred one advertised by the ASPSP
+func (b AuthoriserBuilder) WithAuthMethod(authMethod string) AuthoriserBuilder {
+	b.authMethod = authMethod
+	return b
+}
+
 func (b AuthoriserBuilder) WithRedirectURIs(redirectURIs []string) AuthoriserBuilder {
 	b.redirectURIs = redirectURIs
 	return b
@@ -78,7 +94,12 @@ func (b AuthoriserBuilder) WithResponseTypes(responseTypes []string) AuthoriserB
 	return b
 }
 
-func (b AuthoriserBuilder) WithPrivateKey(privateKey *rsa.PrivateKey) AuthoriserBuilder {
+func (b AuthoriserBuilder) WithScope(scope string) AuthoriserBuilder {
+	b.scope = scope
+	return b
+}
+
+func (b AuthoriserBuilder) WithPrivateKey(privateKey crypto.Signer) AuthoriserBuilder {
 	b.privateKey = privateKey
 	return b
 }
@@ -101,7 +122,12 @@ func (b AuthoriserBuilder) Build() (Authoriser, error) {
 	if b.tokenEndpointSignMethod == nil {
 		return none{}, errors.New("missing token endpoint signing method from authoriser")
 	}
-	return NewAuthoriser(
+	transportCert := b.transportCert
+	if b.AuthMethod() == "self_signed_tls_client_auth" {
+		transportCert = b.selfSignedCert
+	}
+	return NewAuthoriserForMethod(
+		b.AuthMethod(),
 		b.config,
 		b.ssa,
 		b.aud,
@@ -110,9 +136,23 @@ func (b AuthoriserBuilder) Build() (Authoriser, error) {
 		b.tokenEndpointSignMethod,
 		b.redirectURIs,
 		b.responseTypes,
+		b.scope,
 		b.privateKey,
 		b.jwtExpiration,
-		b.transportCert,
+		transportCert,
 		b.transportCertSubjectDn,
 	), nil
 }
+
+// AuthMethod token endpoint auth method the authoriser is built for,
+// the one forced with WithAuthMethod or else the preferred one advertised by the ASPSP
+func (b AuthoriserBuilder) AuthMethod() string {
+	if b.authMethod != "" {
+		return b.authMethod
+	}
+	methods := SupportedAuthMethods(b.config)
+	if len(methods) == 0 {
+		return ""
+	}
+	return methods[0]
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_AuthoriserBuilder_FailsOnMissingSSA(t *testing.T) {
@@ -55,9 +56,60 @@ func Test_AuthoriserBuilder_Success(t *testing.T) {
 		jwt.SigningMethodPS256,
 		[]string{"/redirect"},
 		[]string{"code", "code id_token"},
+		"accounts openid",
 		&rsa.PrivateKey{},
 		0,
 		cert,
 		"",
 	), authoriser)
 }
+
+func Test_AuthoriserBuilder_WithAuthMethod(t *testing.T) {
+	authoriser, err := NewAuthoriserBuilder().
+		WithOpenIDConfig(openid.Configuration{
+			TokenEndpointAuthMethodsSupported: []string{"tls_client_auth", "client_secret_basic"},
+		}).
+		WithSSA("ssa").
+		WithKID("kid").
+		WithPrivateKey(&rsa.PrivateKey{}).
+		WithTokenEndpointAuthMethod(jwt.SigningMethodPS256).
+		WithAuthMethod("client_secret_basic").
+		Build()
+
+	assert.NoError(t, err)
+	assert.IsType(t, clientSecretBasic{}, authoriser)
+}
+
+func Test_AuthoriserBuilder_AuthMethod(t *testing.T) {
+	builder := NewAuthoriserBuilder().
+		WithOpenIDConfig(openid.Configuration{
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "private_key_jwt"},
+		})
+
+	assert.Equal(t, "private_key_jwt", builder.AuthMethod())
+	assert.Equal(t, "client_secret_basic", builder.WithAuthMethod("client_secret_basic").AuthMethod())
+	assert.Equal(t, "", NewAuthoriserBuilder().AuthMethod())
+}
+
+func Test_AuthoriserBuilder_SelfSignedTlsClientAuthUsesSelfSignedCert(t *testing.T) {
+	transportCert := &x509.Certificate{Raw: []byte("transport")}
+	selfSignedCert := &x509.Certificate{Raw: []byte("self signed")}
+
+	authoriser, err := NewAuthoriserBuilder().
+		WithOpenIDConfig(openid.Configuration{
+			TokenEndpointAuthMethodsSupported: []string{"self_signed_tls_client_auth"},
+		}).
+		WithSSA("ssa").
+		WithKID("kid").
+		WithPrivateKey(&rsa.PrivateKey{}).
+		WithTokenEndpointAuthMethod(jwt.SigningMethodPS256).
+		WithTransportCert(transportCert).
+		WithSelfSignedCert(selfSignedCert).
+		Build()
+
+	require.NoError(t, err)
+	require.IsType(t, tlsClientAuth{}, authoriser)
+	signer := authoriser.(tlsClientAuth).signer.(jwtSigner)
+	assert.Equal(t, selfSignedCert, signer.transportCert)
+	assert.Equal(t, "self_signed_tls_client_auth", signer.tokenEndpointAuthMethod)
+}
@@ -33,6 +33,7 @@ func TestNewAuther_ReturnsClientSecretBasic(t *testing.T) {
 		jwt.SigningMethodPS256,
 		[]string{},
 		nil,
+		"accounts openid",
 		&rsa.PrivateKey{},
 		time.Hour,
 		nil,
@@ -56,6 +57,7 @@ func TestNewAuther_ReturnsPrivateKeyJwt(t *testing.T) {
 		jwt.SigningMethodPS256,
 		[]string{},
 		[]string{},
+		"accounts openid",
 		&rsa.PrivateKey{},
 		time.Hour,
 		nil,
@@ -79,6 +81,7 @@ func TestNewAuther_ReturnsTlsClientAuth(t *testing.T) {
 		jwt.SigningMethodPS256,
 		[]string{},
 		[]string{},
+		"accounts openid",
 		&rsa.PrivateKey{},
 		time.Hour,
 		nil,
@@ -102,6 +105,7 @@ func TestNewAuther_ReturnsNoAuther(t *testing.T) {
 		jwt.SigningMethodPS256,
 		[]string{},
 		[]string{},
+		"accounts openid",
 		&rsa.PrivateKey{},
 		time.Hour,
 		nil,
@@ -110,3 +114,72 @@ func TestNewAuther_ReturnsNoAuther(t *testing.T) {
 
 	assert.IsType(t, none{}, auther)
 }
+
+func TestSupportedAuthMethods_InOrderOfPreference(t *testing.T) {
+	openIdConfig := openid.Configuration{
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "unknown", "private_key_jwt", "tls_client_auth"},
+	}
+
+	methods := SupportedAuthMethods(openIdConfig)
+
+	assert.Equal(t, []string{"tls_client_auth", "private_key_jwt", "client_secret_basic"}, methods)
+}
+
+func TestNewAuthoriserForMethod_ReturnsRequestedMethod(t *testing.T) {
+	openIdConfig := openid.Configuration{
+		TokenEndpointAuthMethodsSupported: []string{"tls_client_auth", "client_secret_jwt"},
+	}
+
+	auther := NewAuthoriserForMethod(
+		"client_secret_jwt",
+		openIdConfig,
+		"ssa",
+		"aud",
+		"kid",
+		"softwareID",
+		jwt.SigningMethodPS256,
+		[]string{},
+		[]string{},
+		"accounts openid",
+		&rsa.PrivateKey{},
+		time.Hour,
+		nil,
+		"",
+	)
+
+	assert.IsType(t, clientSecretJWT{}, auther)
+}
+
+func TestNewAuther_ReturnsClientSecretPost(t *testing.T) {
+	openIdConfig := openid.Configuration{
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_post"},
+	}
+
+	auther := NewAuthoriser(
+		openIdConfig,
+		"ssa",
+		"aud",
+		"kid",
+		"softwareID",
+		jwt.SigningMethodPS256,
+		[]string{},
+		nil,
+		"accounts openid",
+		&rsa.PrivateKey{},
+		time.Hour,
+		nil,
+		"",
+	)
+
+	assert.IsType(t, clientSecretPost{}, auther)
+}
+
+func TestIdTokenSignAlg_ResolvesFromIdTokenMetadata(t *testing.T) {
+	assert.Equal(t, "PS256", idTokenSignAlg(openid.Configuration{}, jwt.SigningMethodPS256))
+
+	config := openid.Configuration{IdTokenSigningAlgSupported: []string{"RS256", "PS256"}}
+	assert.Equal(t, "PS256", idTokenSignAlg(config, jwt.SigningMethodPS256))
+
+	config = openid.Configuration{IdTokenSigningAlgSupported: []string{"RS256", "ES256"}}
+	assert.Equal(t, "RS256", idTokenSignAlg(config, jwt.SigningMethodPS256))
+}
@@ -4,6 +4,7 @@ type CredentialsGrantResponse struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
 }
 
 type GrantToken CredentialsGrantResponse
@@ -34,8 +34,10 @@ func (c clientSecretBasic) Client(response []byte) (client.Client, error) {
 }
 
 type OBClientRegistrationResponse struct {
-	ClientID     string `json:"client_id"`
-	ClientSecret string `json:"client_secret,omitempty"`
+	ClientID                string `json:"client_id"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
 }
 
 func (c clientSecretBasic) Claims() (string, error) {
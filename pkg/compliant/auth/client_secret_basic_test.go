@@ -25,8 +25,10 @@ func TestNewClientSecretBasicAuther_Claims(t *testing.T) {
 			"kid",
 			"private_key_jwt",
 			"none",
+			"RS256",
 			[]string{},
 			nil,
+			"accounts openid",
 			privateKey,
 			time.Hour,
 			nil,
@@ -53,8 +55,10 @@ func TestClientSecretBasicAuther_Client_ReturnsAClient(t *testing.T) {
 			"kid",
 			"private_key_jwt",
 			"none",
+			"RS256",
 			[]string{},
 			nil,
+			"accounts openid",
 			privateKey,
 			time.Hour,
 			nil,
@@ -64,7 +68,7 @@ func TestClientSecretBasicAuther_Client_ReturnsAClient(t *testing.T) {
 
 	client, err := auther.Client([]byte(`{"client_id": "12345", "client_secret": "54321"}`))
 	require.NoError(t, err)
-	r, err := client.CredentialsGrantRequest()
+	r, err := client.CredentialsGrantRequest("")
 	require.NoError(t, err)
 	assert.Equal(t, "12345", client.Id())
 
@@ -23,8 +23,10 @@ func TestNewClientSecretJWT_Claims(t *testing.T) {
 			"kid",
 			"client_secret_jwt",
 			"none",
+			"RS256",
 			[]string{},
 			nil,
+			"accounts openid",
 			privateKey,
 			time.Hour,
 			nil,
@@ -51,8 +53,10 @@ func TestClientSecretJWT_Client_ReturnsAClient(t *testing.T) {
 			"kid",
 			"client_secret_jwt",
 			"none",
+			"RS256",
 			[]string{},
 			nil,
+			"accounts openid",
 			privateKey,
 			time.Hour,
 			nil,
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"io/ioutil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSecretPostAuther_Client_ReturnsAClient(t *testing.T) {
+	privateKey, err := certs.ParseRsaPrivateKeyFromPemFile("testdata/private-sign.key")
+	require.NoError(t, err)
+	auther := NewClientSecretPost(
+		"tokenEndpoint",
+		NewJwtSigner(
+			jwt.SigningMethodRS256,
+			"ssa",
+			"issuer",
+			"aud",
+			"kid",
+			"client_secret_post",
+			"none",
+			"RS256",
+			[]string{},
+			nil,
+			"accounts openid",
+			privateKey,
+			time.Hour,
+			nil,
+			"",
+		),
+	)
+
+	client, err := auther.Client([]byte(`{"client_id": "12345", "client_secret": "54321"}`))
+	require.NoError(t, err)
+	r, err := client.CredentialsGrantRequest("")
+	require.NoError(t, err)
+	assert.Equal(t, "12345", client.Id())
+	assert.Equal(t, "", r.Header.Get("Authorization"))
+
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	values, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, "12345", values.Get("client_id"))
+	assert.Equal(t, "54321", values.Get("client_secret"))
+}
@@ -2,7 +2,7 @@ package auth
 
 import (
 	"bytes"
-	"crypto/rsa"
+	"crypto"
 	"encoding/json"
 
 	"github.com/dgrijalva/jwt-go"
@@ -14,14 +14,14 @@ import (
 type clientPrivateKeyJwt struct {
 	tokenEndpoint    string
 	signingAlgorithm jwt.SigningMethod
-	privateKey       *rsa.PrivateKey
+	privateKey       crypto.Signer
 	signer           Signer
 }
 
 func NewClientPrivateKeyJwt(
 	tokenEndpoint string,
 	signingAlgorithm jwt.SigningMethod,
-	privateKey *rsa.PrivateKey,
+	privateKey crypto.Signer,
 	signer Signer,
 ) Authoriser {
 	return clientPrivateKeyJwt{
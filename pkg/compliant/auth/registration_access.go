@@ -0,0 +1,13 @@
+package auth
+
+// RegistrationAccess is the RFC7592 client configuration endpoint and bearer token
+// an ASPSP can return on registration to manage the client with, instead of client credentials grant tokens
+type RegistrationAccess struct {
+	ClientURI   string
+	AccessToken string
+}
+
+// Enabled is true when the ASPSP returned both registration_client_uri and registration_access_token
+func (r RegistrationAccess) Enabled() bool {
+	return r.ClientURI != "" && r.AccessToken != ""
+}
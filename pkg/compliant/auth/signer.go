@@ -1,10 +1,11 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"time"
 
+	"github.com/OpenBankingUK/conformance-dcr/pkg/jwks"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -22,9 +23,11 @@ type jwtSigner struct {
 	kID                     string
 	tokenEndpointAuthMethod string
 	requestObjectSignAlg    string
+	idTokenSignAlg          string
 	redirectURIs            []string
 	responseTypes           []string
-	privateKey              *rsa.PrivateKey
+	scope                   string
+	privateKey              crypto.Signer
 	jwtExpiration           time.Duration
 	transportCert           *x509.Certificate
 	transportSubjectDn      string
@@ -38,9 +41,11 @@ func NewJwtSigner(
 	kID,
 	tokenEndpointAuthMethod string,
 	requestObjectSignAlg string,
+	idTokenSignAlg string,
 	redirectURIs []string,
 	responseTypes []string,
-	privateKey *rsa.PrivateKey,
+	scope string,
+	privateKey crypto.Signer,
 	jwtExpiration time.Duration,
 	transportCert *x509.Certificate,
 	transportSubjectDn string,
@@ -53,8 +58,10 @@ func NewJwtSigner(
 		kID:                     kID,
 		tokenEndpointAuthMethod: tokenEndpointAuthMethod,
 		requestObjectSignAlg:    requestObjectSignAlg,
+		idTokenSignAlg:          idTokenSignAlg,
 		redirectURIs:            redirectURIs,
 		responseTypes:           responseTypes,
+		scope:                   scope,
 		privateKey:              privateKey,
 		jwtExpiration:           jwtExpiration,
 		transportCert:           transportCert,
@@ -99,9 +106,9 @@ func (s jwtSigner) Claims() (string, error) {
 		"redirect_uris":                s.redirectURIs,
 		"token_endpoint_auth_method":   s.tokenEndpointAuthMethod,
 		"software_statement":           s.ssa,
-		"scope":                        "accounts openid",
+		"scope":                        s.scope,
 		"request_object_signing_alg":   s.requestObjectSignAlg,
-		"id_token_signed_response_alg": s.signingAlgorithm.Alg(),
+		"id_token_signed_response_alg": s.idTokenSignAlg,
 	}
 
 	if s.responseTypes != nil {
@@ -114,6 +121,10 @@ func (s jwtSigner) Claims() (string, error) {
 		return "", err
 	}
 
+	if err = s.addSelfSignedTlsClientAuthClaims(claims); err != nil {
+		return "", err
+	}
+
 	s.addSigningAlgClaims(claims)
 
 	token := jwt.NewWithClaims(s.signingAlgorithm, claims)
@@ -151,3 +162,23 @@ func (s jwtSigner) addTlsClientAuthClaims(claims jwt.MapClaims) error {
 
 	return nil
 }
+
+// self signed certificates can't be identified by subject DN, the certificate is registered in the client jwks
+func (s jwtSigner) addSelfSignedTlsClientAuthClaims(claims jwt.MapClaims) error {
+	if s.tokenEndpointAuthMethod != "self_signed_tls_client_auth" {
+		return nil
+	}
+
+	if s.transportCert == nil {
+		return errors.New("self signed transport cert not available")
+	}
+
+	jwk, err := jwks.NewCertificateJWK(s.transportCert, "", "")
+	if err != nil {
+		return errors.Wrap(err, "self signed tls client auth jwks")
+	}
+	jwk.Kid = jwk.X5tS256
+	claims["jwks"] = jwks.NewSet(jwk)
+
+	return nil
+}
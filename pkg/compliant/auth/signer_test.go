@@ -1,9 +1,13 @@
 package auth
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/base64"
 	"fmt"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
 	"github.com/dgrijalva/jwt-go"
@@ -24,8 +28,10 @@ func TestNewJwtSigner(t *testing.T) {
 		"kid",
 		"private_key_jwt",
 		"none",
+		"PS256",
 		[]string{"/redirect"},
 		[]string{"code", "code id_token"},
+		"accounts openid",
 		privateKey,
 		time.Hour,
 		&x509.Certificate{},
@@ -49,7 +55,7 @@ func TestNewJwtSigner(t *testing.T) {
 	assert.Equal(t, "web", claims["application_type"])
 	assert.Equal(t, "aud", claims["aud"])
 	assert.Equal(t, []interface{}{"authorization_code", "client_credentials"}, claims["grant_types"])
-	assert.Equal(t, "RS256", claims["id_token_signed_response_alg"])
+	assert.Equal(t, "PS256", claims["id_token_signed_response_alg"])
 	assert.Equal(t, "issuer", claims["iss"])
 	assert.Equal(t, "kid", token.Header["kid"])
 	assert.Equal(t, []interface{}{"/redirect"}, claims["redirect_uris"])
@@ -74,8 +80,10 @@ func TestNewJwtSigner_TlsClientAuthAddSubjectToClaims(t *testing.T) {
 		"kid",
 		"tls_client_auth",
 		"none",
+		"RS256",
 		[]string{"/redirect"},
 		[]string{"code", "code id_token"},
+		"accounts openid",
 		privateKey,
 		time.Hour,
 		&x509.Certificate{Subject: pkix.Name{Organization: []string{"OB"}}},
@@ -111,8 +119,10 @@ func TestNewJwtSigner_TlsClientAuthAddConfigurableSubjectToClaims(t *testing.T)
 		"kid",
 		"tls_client_auth",
 		"none",
+		"RS256",
 		[]string{"/redirect"},
 		[]string{"code", "code id_token"},
+		"accounts openid",
 		privateKey,
 		time.Hour,
 		&x509.Certificate{Subject: pkix.Name{Organization: []string{"OB"}}},
@@ -152,8 +162,10 @@ func TestNewJwtSigner_TlsClientAuthDoesNotPanicOnMissingCert(t *testing.T) {
 		"kid",
 		"tls_client_auth",
 		"none",
+		"RS256",
 		[]string{"/redirect"},
 		[]string{"code", "code id_token"},
+		"accounts openid",
 		privateKey,
 		time.Hour,
 		nil,
@@ -176,10 +188,12 @@ func TestNewJwtSigner_OmitsEmptyResponseTypes(t *testing.T) {
 		"kid",
 		"tls_client_auth",
 		"none",
+		"RS256",
 		[]string{"/redirect"},
 
 		// testing empty/nil
 		nil,
+		"accounts openid",
 
 		privateKey,
 		time.Hour,
@@ -191,3 +205,103 @@ func TestNewJwtSigner_OmitsEmptyResponseTypes(t *testing.T) {
 	_, exists := claims["response_types"]
 	assert.False(t, exists)
 }
+
+func TestNewJwtSigner_SignsWithECKey(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer := NewJwtSigner(
+		jwt.SigningMethodES256,
+		"ssa",
+		"issuer",
+		"aud",
+		"kid",
+		"private_key_jwt",
+		"none",
+		"ES256",
+		[]string{"/redirect"},
+		[]string{"code", "code id_token"},
+		"accounts openid",
+		privateKey,
+		time.Hour,
+		&x509.Certificate{},
+		"",
+	)
+
+	signedClaims, err := signer.Claims()
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(signedClaims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return privateKey.Public(), nil
+	})
+	require.NoError(t, err)
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	assert.True(t, ok)
+	assert.Equal(t, "ES256", token.Header["alg"])
+	assert.Equal(t, "ES256", claims["id_token_signed_response_alg"])
+	assert.Equal(t, "ES256", claims["token_endpoint_auth_signing_alg"])
+}
+
+func TestNewJwtSigner_SelfSignedTlsClientAuthAddsJwksToClaims(t *testing.T) {
+	privateKey, err := certs.ParseRsaPrivateKeyFromPemFile("testdata/private-sign.key")
+	require.NoError(t, err)
+	selfSigned, err := certs.NewSelfSigned(pkix.Name{CommonName: "tpp"}, time.Hour)
+	require.NoError(t, err)
+	signer := NewJwtSigner(
+		jwt.SigningMethodRS256,
+		"ssa",
+		"issuer",
+		"aud",
+		"kid",
+		"self_signed_tls_client_auth",
+		"none",
+		"RS256",
+		[]string{"/redirect"},
+		nil,
+		"accounts openid",
+		privateKey,
+		time.Hour,
+		selfSigned.Cert,
+		"",
+	)
+
+	_, claims := getJwtClaims(t, signer, privateKey)
+
+	assert.Equal(t, "self_signed_tls_client_auth", claims["token_endpoint_auth_method"])
+	assert.Nil(t, claims["tls_client_auth_subject_dn"])
+	keys := claims["jwks"].(map[string]interface{})["keys"].([]interface{})
+	require.Len(t, keys, 1)
+	key := keys[0].(map[string]interface{})
+	assert.Equal(t, "EC", key["kty"])
+	assert.Equal(t, key["x5t#S256"], key["kid"])
+	assert.Equal(t, []interface{}{base64.StdEncoding.EncodeToString(selfSigned.Cert.Raw)}, key["x5c"])
+}
+
+func TestNewJwtSigner_SelfSignedTlsClientAuthFailsOnMissingCert(t *testing.T) {
+	privateKey, err := certs.ParseRsaPrivateKeyFromPemFile("testdata/private-sign.key")
+	require.NoError(t, err)
+	signer := NewJwtSigner(
+		jwt.SigningMethodRS256,
+		"ssa",
+		"issuer",
+		"aud",
+		"kid",
+		"self_signed_tls_client_auth",
+		"none",
+		"RS256",
+		[]string{"/redirect"},
+		nil,
+		"accounts openid",
+		privateKey,
+		time.Hour,
+		nil,
+		"",
+	)
+
+	_, err = signer.Claims()
+
+	assert.EqualError(t, err, "self signed transport cert not available")
+}
@@ -1,11 +1,14 @@
 package compliant
 
 import (
+	"crypto/x509"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
 	"net/http"
 	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 )
 
@@ -53,10 +56,11 @@ func newDefaultHttpClient() *http.Client {
 }
 
 const (
-	responseCtxKey   = "response"
-	clientCtxKey     = "software_client"
-	jwtClaimsCtxKey  = "jwt_claims"
-	grantTokenCtxKey = "grant_token"
+	responseCtxKey           = "response"
+	clientCtxKey             = "software_client"
+	jwtClaimsCtxKey          = "jwt_claims"
+	grantTokenCtxKey         = "grant_token"
+	registrationAccessCtxKey = "registration_access"
 )
 
 func (t *testCaseBuilder) WithHttpClient(client *http.Client) *testCaseBuilder {
@@ -69,6 +73,12 @@ func (t *testCaseBuilder) Get(url string) *testCaseBuilder {
 	return t
 }
 
+// GetNamed same as Get with a step name that doesn't depend on the url, for output compared across environments
+func (t *testCaseBuilder) GetNamed(stepName, url string) *testCaseBuilder {
+	t.steps = append(t.steps, step.NewNamedGetRequest(stepName, url, responseCtxKey, t.httpClient))
+	return t
+}
+
 func (t *testCaseBuilder) AssertStatusCodeOk() *testCaseBuilder {
 	nextStep := step.NewAssertStatus(http.StatusOK, responseCtxKey)
 	t.steps = append(t.steps, nextStep)
@@ -93,6 +103,12 @@ func (t *testCaseBuilder) AssertStatusCodeCreated() *testCaseBuilder {
 	return t
 }
 
+func (t *testCaseBuilder) AssertInteractionIDEchoed() *testCaseBuilder {
+	nextStep := step.NewAssertInteractionIDEchoed(responseCtxKey)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
 func (t *testCaseBuilder) AssertContextTypeApplicationHtml() *testCaseBuilder {
 	nextStep := step.NewAssertContentType(responseCtxKey, "application/html")
 	t.steps = append(t.steps, nextStep)
@@ -118,6 +134,7 @@ func (t *testCaseBuilder) ClientUpdate(registrationEndpoint string) *testCaseBui
 		responseCtxKey,
 		clientCtxKey,
 		grantTokenCtxKey,
+		registrationAccessCtxKey,
 		t.httpClient,
 	)
 	t.steps = append(t.steps, nextStep)
@@ -125,13 +142,26 @@ func (t *testCaseBuilder) ClientUpdate(registrationEndpoint string) *testCaseBui
 }
 
 func (t *testCaseBuilder) ClientDelete(registrationEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientDelete(registrationEndpoint, clientCtxKey, grantTokenCtxKey, t.httpClient)
+	nextStep := step.NewClientDelete(
+		registrationEndpoint,
+		clientCtxKey,
+		grantTokenCtxKey,
+		registrationAccessCtxKey,
+		t.httpClient,
+	)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
 
 func (t *testCaseBuilder) ClientRetrieve(registrationEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientRetrieve(responseCtxKey, registrationEndpoint, clientCtxKey, grantTokenCtxKey, t.httpClient)
+	nextStep := step.NewClientRetrieve(
+		responseCtxKey,
+		registrationEndpoint,
+		clientCtxKey,
+		grantTokenCtxKey,
+		registrationAccessCtxKey,
+		t.httpClient,
+	)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
@@ -142,6 +172,19 @@ func (t *testCaseBuilder) AssertValidSchemaResponse(validator schema.Validator)
 	return t
 }
 
+func (t *testCaseBuilder) AssertRegisteredMetadataMatchesRequest() *testCaseBuilder {
+	nextStep := step.NewClientRegisterMetadata(jwtClaimsCtxKey, responseCtxKey)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+// AssertClientUpdatePersisted scopeUpdated enforces the scope is persisted when it's the field the update changed
+func (t *testCaseBuilder) AssertClientUpdatePersisted(scopeUpdated bool) *testCaseBuilder {
+	nextStep := step.NewClientUpdatePersisted(jwtClaimsCtxKey, responseCtxKey, clientCtxKey, scopeUpdated)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
 func (t *testCaseBuilder) SetInvalidGrantToken() *testCaseBuilder {
 	nextStep := step.NewSetInvalidGrantToken(grantTokenCtxKey)
 	t.steps = append(t.steps, nextStep)
@@ -149,7 +192,7 @@ func (t *testCaseBuilder) SetInvalidGrantToken() *testCaseBuilder {
 }
 
 func (t *testCaseBuilder) ParseClientRegisterResponse(authoriserBuilder auth.AuthoriserBuilder) *testCaseBuilder {
-	nextStep := step.NewClientRegisterResponse(responseCtxKey, clientCtxKey, authoriserBuilder)
+	nextStep := step.NewClientRegisterResponse(responseCtxKey, clientCtxKey, registrationAccessCtxKey, authoriserBuilder)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
@@ -166,8 +209,118 @@ func (t *testCaseBuilder) ValidateRegistrationEndpoint(registrationEndpoint *str
 	return t
 }
 
-func (t *testCaseBuilder) GetClientCredentialsGrant(tokenEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientCredentialsGrant(grantTokenCtxKey, clientCtxKey, tokenEndpoint, t.httpClient)
+func (t *testCaseBuilder) ValidateOpenIDConfig(config openid.Configuration, wellknownEndpoint string) *testCaseBuilder {
+	nextStep := step.NewValidateOpenIDConfig(config, wellknownEndpoint)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) GetClientCredentialsGrant(tokenEndpoint string, scopes []string) *testCaseBuilder {
+	nextStep := step.NewClientCredentialsGrantWithScope(
+		grantTokenCtxKey,
+		clientCtxKey,
+		tokenEndpoint,
+		scopes,
+		t.httpClient,
+	)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertUnregisteredScopeRejected(scope string) *testCaseBuilder {
+	nextStep := step.NewClientCredentialsGrantUnregisteredScope(clientCtxKey, scope, t.httpClient)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertMisplacedClientSecretRejected() *testCaseBuilder {
+	nextStep := step.NewClientCredentialsGrantMisplacedSecret(clientCtxKey, t.httpClient)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertValidClientCredentialsGrantResponse() *testCaseBuilder {
+	nextStep := step.NewValidateCredentialsGrantResponse(clientCtxKey, t.httpClient)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertInvalidClientCredentialsGrantRejected(invalid client.InvalidGrant) *testCaseBuilder {
+	nextStep := step.NewClientCredentialsGrantRejected(clientCtxKey, invalid, t.httpClient)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertCertificateBoundToken(
+	introspectionEndpoint string,
+	cert *x509.Certificate,
+) *testCaseBuilder {
+	nextStep := step.NewCertificateBoundToken(grantTokenCtxKey, clientCtxKey, introspectionEndpoint, cert, t.httpClient)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+// AssertBoundTokenRejected uses the test case http client, which must have a different client certificate
+func (t *testCaseBuilder) AssertBoundTokenRejected(registrationEndpoint string) *testCaseBuilder {
+	nextStep := step.NewBoundTokenRejected(
+		registrationEndpoint,
+		clientCtxKey,
+		grantTokenCtxKey,
+		registrationAccessCtxKey,
+		t.httpClient,
+	)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertFAPITLS() *testCaseBuilder {
+	nextStep := step.NewAssertFAPITLS(responseCtxKey)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertServerCertNotExpiring(days int) *testCaseBuilder {
+	nextStep := step.NewAssertServerCertNotExpiring(responseCtxKey, days)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+// AssertClientCertRequired uses the test case http client, which must not have a client certificate
+func (t *testCaseBuilder) AssertClientCertRequired(url string) *testCaseBuilder {
+	nextStep := step.NewAssertHandshakeRejected(
+		"Assert server requires a client certificate",
+		url,
+		"without client certificate",
+		t.httpClient,
+	)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+// AssertNonFAPICipherSuitesRejected uses the test case http client, which must only offer non FAPI cipher suites
+func (t *testCaseBuilder) AssertNonFAPICipherSuitesRejected(url string) *testCaseBuilder {
+	nextStep := step.NewAssertHandshakeRejected(
+		"Assert server rejects cipher suites not permitted by FAPI",
+		url,
+		"with cipher suites not permitted by FAPI",
+		t.httpClient,
+	)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+// AssertInvalidCertRegisterRejected uses the test case http client, which must have an invalid transport certificate
+func (t *testCaseBuilder) AssertInvalidCertRegisterRejected(
+	registrationEndpoint, certDescription string,
+) *testCaseBuilder {
+	nextStep := step.NewInvalidCertRegisterRejected(registrationEndpoint, jwtClaimsCtxKey, certDescription, t.httpClient)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+// AssertInvalidCertGrantRejected uses the test case http client, which must have an invalid transport certificate
+func (t *testCaseBuilder) AssertInvalidCertGrantRejected(certDescription string) *testCaseBuilder {
+	nextStep := step.NewInvalidCertCredentialsGrantRejected(clientCtxKey, certDescription, t.httpClient)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,6 +42,7 @@ func TestNewTestCaseBuilder(t *testing.T) {
 	tc := NewTestCaseBuilder("test case").
 		WithHttpClient(&http.Client{}).
 		Get("www.google.com").
+		GetNamed("get", "www.google.com").
 		AssertStatusCodeOk().
 		AssertStatusCodeUnauthorized().
 		AssertStatusCodeBadRequest().
@@ -53,10 +55,19 @@ func TestNewTestCaseBuilder(t *testing.T) {
 		ClientDelete(sampleEndpoint).
 		ParseClientRetrieveResponse(sampleEndpoint).
 		AssertValidSchemaResponse(validator).
+		AssertRegisteredMetadataMatchesRequest().
+		AssertClientUpdatePersisted(false).
 		SetInvalidGrantToken().
 		ValidateRegistrationEndpoint(someUrl).
-		GetClientCredentialsGrant(sampleEndpoint)
+		ValidateOpenIDConfig(openid.Configuration{}, sampleEndpoint).
+		GetClientCredentialsGrant(sampleEndpoint, []string{"accounts"}).
+		AssertMisplacedClientSecretRejected().
+		AssertCertificateBoundToken(sampleEndpoint, nil).
+		AssertBoundTokenRejected(sampleEndpoint).
+		AssertValidClientCredentialsGrantResponse().
+		AssertInvalidClientCredentialsGrantRejected(client.ExpiredAssertion).
+		AssertUnregisteredScopeRejected("payments")
 
 	assert.Equal(t, "test case", tc.name)
-	assert.Len(t, tc.steps, 16)
+	assert.Len(t, tc.steps, 26)
 }
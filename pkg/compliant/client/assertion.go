@@ -0,0 +1,42 @@
+package client
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+const jwtBearerAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// assertion holds the client assertion claims for private_key_jwt and client_secret_jwt
+// that the invalid grant clients override
+type assertion struct {
+	audience   string
+	expiration time.Duration
+	// jti is generated for each request when empty
+	jti string
+}
+
+func newAssertion(tokenEndpoint string) assertion {
+	return assertion{
+		audience:   tokenEndpoint,
+		expiration: 30 * time.Minute,
+	}
+}
+
+func (a assertion) claims(id string) jwt.MapClaims {
+	now := time.Now()
+	jti := a.jti
+	if jti == "" {
+		jti = uuid.New().String()
+	}
+	return jwt.MapClaims{
+		"iss": id,
+		"sub": id,
+		"aud": a.audience,
+		"iat": now.Unix(),
+		"exp": now.Add(a.expiration).Unix(),
+		"jti": jti,
+	}
+}
@@ -6,7 +6,8 @@ import (
 
 type Client interface {
 	Id() string
-	CredentialsGrantRequest() (*http.Request, error)
+	// CredentialsGrantRequest builds a client credentials grant token request for the given scope
+	CredentialsGrantRequest(scope string) (*http.Request, error)
 }
 
 type noClient struct {
@@ -20,6 +21,6 @@ func (c noClient) Id() string {
 	return ""
 }
 
-func (c noClient) CredentialsGrantRequest() (*http.Request, error) {
+func (c noClient) CredentialsGrantRequest(scope string) (*http.Request, error) {
 	return nil, nil
 }
@@ -27,11 +27,11 @@ func (c clientSecretBasic) Id() string {
 	return c.id
 }
 
-func (c clientSecretBasic) CredentialsGrantRequest() (*http.Request, error) {
+func (c clientSecretBasic) CredentialsGrantRequest(scope string) (*http.Request, error) {
 	token := fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(c.authClientKey())))
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
-	data.Set("scope", "")
+	data.Set("scope", scope)
 	reqBody := strings.NewReader(data.Encode())
 	r, err := http.NewRequest(http.MethodPost, c.tokenEndpoint, reqBody)
 	if err != nil {
@@ -15,7 +15,7 @@ func TestClientBasic(t *testing.T) {
 
 	expectedTokenHeader := fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte("id:secret")))
 
-	request, err := client.CredentialsGrantRequest()
+	request, err := client.CredentialsGrantRequest("")
 	require.NoError(t, err)
 	assert.Equal(t, "id", client.Id())
 	assert.Equal(t, expectedTokenHeader, request.Header.Get("Authorization"))
@@ -2,18 +2,17 @@ package client
 
 import (
 	"github.com/dgrijalva/jwt-go"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 )
 
 type clientSecretJwt struct {
 	id            string
 	tokenEndpoint string
 	clientSecret  string
+	assertion     assertion
 }
 
 func NewClientSecretJwt(id, clientSecret, tokenEndpoint string) Client {
@@ -21,6 +20,7 @@ func NewClientSecretJwt(id, clientSecret, tokenEndpoint string) Client {
 		id:            id,
 		tokenEndpoint: tokenEndpoint,
 		clientSecret:  clientSecret,
+		assertion:     newAssertion(tokenEndpoint),
 	}
 }
 
@@ -28,19 +28,8 @@ func (c clientSecretJwt) Id() string {
 	return c.id
 }
 
-func (c clientSecretJwt) CredentialsGrantRequest() (*http.Request, error) {
-	now := time.Now()
-	iat := now.Unix()
-	exp := now.Add(30 * time.Minute).Unix()
-	jti := uuid.New().String()
-	claims := jwt.MapClaims{
-		"iss": c.id,
-		"sub": c.id,
-		"aud": c.tokenEndpoint,
-		"iat": iat,
-		"exp": exp,
-		"jti": jti,
-	}
+func (c clientSecretJwt) CredentialsGrantRequest(scope string) (*http.Request, error) {
+	claims := c.assertion.claims(c.id)
 
 	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(c.clientSecret))
 	if err != nil {
@@ -48,8 +37,8 @@ func (c clientSecretJwt) CredentialsGrantRequest() (*http.Request, error) {
 	}
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
-	data.Set("scope", "")
-	data.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	data.Set("scope", scope)
+	data.Set("client_assertion_type", jwtBearerAssertionType)
 	data.Set("client_assertion", token)
 	reqBody := strings.NewReader(data.Encode())
 	r, err := http.NewRequest(http.MethodPost, c.tokenEndpoint, reqBody)
@@ -11,7 +11,7 @@ import (
 func TestClientSecretJWT(t *testing.T) {
 	client := NewClientSecretJwt("id", "secret", "/token_endpoint")
 
-	request, err := client.CredentialsGrantRequest()
+	request, err := client.CredentialsGrantRequest("")
 	require.NoError(t, err)
 	assert.Equal(t, "id", client.Id())
 
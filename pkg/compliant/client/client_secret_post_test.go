@@ -0,0 +1,29 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/url"
+	"testing"
+)
+
+func TestClientSecretPost(t *testing.T) {
+	client := NewClientSecretPost("id", "secret", "http://endpoint")
+
+	request, err := client.CredentialsGrantRequest("accounts openid")
+	require.NoError(t, err)
+	assert.Equal(t, "id", client.Id())
+	assert.Equal(t, "", request.Header.Get("Authorization"))
+
+	bodyByes, err := ioutil.ReadAll(request.Body)
+	require.NoError(t, err)
+
+	bodyDecoded, err := url.ParseQuery(string(bodyByes))
+	require.NoError(t, err)
+
+	assert.Equal(t, "client_credentials", bodyDecoded.Get("grant_type"))
+	assert.Equal(t, "accounts openid", bodyDecoded.Get("scope"))
+	assert.Equal(t, "id", bodyDecoded.Get("client_id"))
+	assert.Equal(t, "secret", bodyDecoded.Get("client_secret"))
+}
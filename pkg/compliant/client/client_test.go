@@ -10,7 +10,7 @@ import (
 func TestNoClient(t *testing.T) {
 	client := NewNoClient()
 
-	_, err := client.CredentialsGrantRequest()
+	_, err := client.CredentialsGrantRequest("")
 	require.NoError(t, err)
 	assert.Equal(t, "", client.Id())
 }
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// InvalidGrant is a way of making a client credentials grant request that a token endpoint must reject
+type InvalidGrant int
+
+const (
+	WrongAssertionAudience InvalidGrant = iota
+	ExpiredAssertion
+	ReusedAssertionJti
+	WrongAssertionType
+	UnregisteredGrantType
+)
+
+// unregisteredGrantType is not in the grant_types of the software statement claims
+const unregisteredGrantType = "password"
+
+func (i InvalidGrant) String() string {
+	switch i {
+	case WrongAssertionAudience:
+		return "wrong client assertion audience"
+	case ExpiredAssertion:
+		return "expired client assertion"
+	case ReusedAssertionJti:
+		return "reused client assertion jti"
+	case WrongAssertionType:
+		return "wrong client assertion type"
+	case UnregisteredGrantType:
+		return "grant type not registered"
+	}
+	return "unknown invalid grant"
+}
+
+// RequiresAssertion is true when the invalid grant only applies to private_key_jwt and client_secret_jwt
+func (i InvalidGrant) RequiresAssertion() bool {
+	return i != UnregisteredGrantType
+}
+
+// NewInvalidGrantClient returns a client whose client credentials grant requests are invalid,
+// a ReusedAssertionJti client sends the same jti on every request so the second one must be rejected
+func NewInvalidGrantClient(c Client, invalid InvalidGrant) (Client, error) {
+	switch invalid {
+	case WrongAssertionType:
+		if !hasAssertion(c) {
+			return nil, errors.New("client does not authenticate with a client assertion")
+		}
+		return formOverrideClient{Client: c, field: "client_assertion_type", value: "client_secret"}, nil
+	case UnregisteredGrantType:
+		return formOverrideClient{Client: c, field: "grant_type", value: unregisteredGrantType}, nil
+	}
+
+	var override func(a assertion) assertion
+	switch invalid {
+	case WrongAssertionAudience:
+		override = func(a assertion) assertion {
+			a.audience = "https://invalid.audience.example.com"
+			return a
+		}
+	case ExpiredAssertion:
+		override = func(a assertion) assertion {
+			a.expiration = -time.Hour
+			return a
+		}
+	case ReusedAssertionJti:
+		jti := uuid.New().String()
+		override = func(a assertion) assertion {
+			a.jti = jti
+			return a
+		}
+	default:
+		return nil, errors.Errorf("unsupported invalid grant %d", invalid)
+	}
+
+	switch assertionClient := c.(type) {
+	case privateKeyJwt:
+		assertionClient.assertion = override(assertionClient.assertion)
+		return assertionClient, nil
+	case clientSecretJwt:
+		assertionClient.assertion = override(assertionClient.assertion)
+		return assertionClient, nil
+	}
+	return nil, errors.New("client does not authenticate with a client assertion")
+}
+
+func hasAssertion(c Client) bool {
+	switch c.(type) {
+	case privateKeyJwt, clientSecretJwt:
+		return true
+	}
+	return false
+}
+
+// formOverrideClient replaces a form field of the wrapped client credentials grant request
+type formOverrideClient struct {
+	Client
+	field string
+	value string
+}
+
+func (c formOverrideClient) CredentialsGrantRequest(scope string) (*http.Request, error) {
+	r, err := c.Client.CredentialsGrantRequest(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading token request body")
+	}
+	data, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing token request body")
+	}
+	data.Set(c.field, c.value)
+
+	override, err := http.NewRequest(r.Method, r.URL.String(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error making token request with invalid %s", c.field)
+	}
+	override.Header = r.Header
+	return override, nil
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestForm(t *testing.T, r *http.Request) url.Values {
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	return form
+}
+
+func assertionClaims(t *testing.T, c Client) jwt.MapClaims {
+	r, err := c.CredentialsGrantRequest("")
+	require.NoError(t, err)
+	claims := jwt.MapClaims{}
+	_, _, err = new(jwt.Parser).ParseUnverified(requestForm(t, r).Get("client_assertion"), claims)
+	require.NoError(t, err)
+	return claims
+}
+
+func TestNewInvalidGrantClient_WrongAssertionAudience(t *testing.T) {
+	c, err := NewInvalidGrantClient(NewClientSecretJwt("id", "secret", "http://token"), WrongAssertionAudience)
+	require.NoError(t, err)
+
+	claims := assertionClaims(t, c)
+
+	assert.Equal(t, "https://invalid.audience.example.com", claims["aud"])
+}
+
+func TestNewInvalidGrantClient_ExpiredAssertion(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	c, err := NewInvalidGrantClient(NewPrivateKeyJwt("id", "http://token", key, jwt.SigningMethodPS256), ExpiredAssertion)
+	require.NoError(t, err)
+
+	claims := assertionClaims(t, c)
+
+	assert.Error(t, claims.Valid())
+}
+
+func TestNewInvalidGrantClient_ReusedAssertionJti(t *testing.T) {
+	c, err := NewInvalidGrantClient(NewClientSecretJwt("id", "secret", "http://token"), ReusedAssertionJti)
+	require.NoError(t, err)
+
+	first := assertionClaims(t, c)
+	second := assertionClaims(t, c)
+
+	assert.NotEmpty(t, first["jti"])
+	assert.Equal(t, first["jti"], second["jti"])
+}
+
+func TestNewInvalidGrantClient_WrongAssertionType(t *testing.T) {
+	c, err := NewInvalidGrantClient(NewClientSecretJwt("id", "secret", "http://token"), WrongAssertionType)
+	require.NoError(t, err)
+
+	r, err := c.CredentialsGrantRequest("")
+	require.NoError(t, err)
+
+	form := requestForm(t, r)
+	assert.Equal(t, "client_secret", form.Get("client_assertion_type"))
+	assert.NotEmpty(t, form.Get("client_assertion"))
+	assert.Equal(t, "http://token", r.URL.String())
+}
+
+func TestNewInvalidGrantClient_UnregisteredGrantType(t *testing.T) {
+	c, err := NewInvalidGrantClient(NewClientSecretBasic("id", "secret", "http://token"), UnregisteredGrantType)
+	require.NoError(t, err)
+
+	r, err := c.CredentialsGrantRequest("")
+	require.NoError(t, err)
+
+	assert.Equal(t, "password", requestForm(t, r).Get("grant_type"))
+	assert.NotEmpty(t, r.Header.Get("Authorization"))
+	assert.Equal(t, "id", c.Id())
+}
+
+func TestNewInvalidGrantClient_FailsWithoutClientAssertion(t *testing.T) {
+	c, err := NewInvalidGrantClient(NewClientSecretPost("id", "secret", "http://token"), ExpiredAssertion)
+
+	assert.EqualError(t, err, "client does not authenticate with a client assertion")
+	assert.Nil(t, c)
+}
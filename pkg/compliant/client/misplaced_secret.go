@@ -0,0 +1,16 @@
+package client
+
+import "github.com/pkg/errors"
+
+// NewMisplacedSecretClient returns a client that sends the secret of a client_secret_basic client
+// in the request body and the secret of a client_secret_post client in the Authorization header,
+// a token endpoint must only accept the secret using the registered auth method
+func NewMisplacedSecretClient(c Client) (Client, error) {
+	switch secretClient := c.(type) {
+	case clientSecretBasic:
+		return NewClientSecretPost(secretClient.id, secretClient.secret, secretClient.tokenEndpoint), nil
+	case clientSecretPost:
+		return NewClientSecretBasic(secretClient.id, secretClient.secret, secretClient.tokenEndpoint), nil
+	}
+	return nil, errors.New("client does not authenticate with a client secret")
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"crypto/rsa"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewMisplacedSecretClient_SendsBasicSecretInBody(t *testing.T) {
+	client, err := NewMisplacedSecretClient(NewClientSecretBasic("id", "secret", "http://endpoint"))
+	require.NoError(t, err)
+
+	assert.Equal(t, NewClientSecretPost("id", "secret", "http://endpoint"), client)
+}
+
+func TestNewMisplacedSecretClient_SendsPostSecretInHeader(t *testing.T) {
+	client, err := NewMisplacedSecretClient(NewClientSecretPost("id", "secret", "http://endpoint"))
+	require.NoError(t, err)
+
+	assert.Equal(t, NewClientSecretBasic("id", "secret", "http://endpoint"), client)
+}
+
+func TestNewMisplacedSecretClient_FailsWithoutClientSecret(t *testing.T) {
+	client, err := NewMisplacedSecretClient(
+		NewPrivateKeyJwt("id", "http://endpoint", &rsa.PrivateKey{}, jwt.SigningMethodPS256),
+	)
+
+	assert.EqualError(t, err, "client does not authenticate with a client secret")
+	assert.Nil(t, client)
+}
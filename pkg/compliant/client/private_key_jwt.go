@@ -1,26 +1,25 @@
 package client
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"github.com/dgrijalva/jwt-go"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 )
 
 type privateKeyJwt struct {
 	id               string
 	tokenEndpoint    string
-	privateKey       *rsa.PrivateKey
+	privateKey       crypto.Signer
 	signingAlgorithm jwt.SigningMethod
+	assertion        assertion
 }
 
 func NewPrivateKeyJwt(
 	id, tokenEndpoint string,
-	privateKey *rsa.PrivateKey,
+	privateKey crypto.Signer,
 	signingAlgorithm jwt.SigningMethod,
 ) Client {
 	return privateKeyJwt{
@@ -28,6 +27,7 @@ func NewPrivateKeyJwt(
 		tokenEndpoint:    tokenEndpoint,
 		privateKey:       privateKey,
 		signingAlgorithm: signingAlgorithm,
+		assertion:        newAssertion(tokenEndpoint),
 	}
 }
 
@@ -35,19 +35,8 @@ func (c privateKeyJwt) Id() string {
 	return c.id
 }
 
-func (c privateKeyJwt) CredentialsGrantRequest() (*http.Request, error) {
-	now := time.Now()
-	iat := now.Unix()
-	exp := now.Add(30 * time.Minute).Unix()
-	jti := uuid.New().String()
-	claims := jwt.MapClaims{
-		"iss": c.id,
-		"sub": c.id,
-		"aud": c.tokenEndpoint,
-		"iat": iat,
-		"exp": exp,
-		"jti": jti,
-	}
+func (c privateKeyJwt) CredentialsGrantRequest(scope string) (*http.Request, error) {
+	claims := c.assertion.claims(c.id)
 
 	token, err := jwt.NewWithClaims(c.signingAlgorithm, claims).SignedString(c.privateKey)
 	if err != nil {
@@ -55,8 +44,8 @@ func (c privateKeyJwt) CredentialsGrantRequest() (*http.Request, error) {
 	}
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
-	data.Set("scope", "")
-	data.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	data.Set("scope", scope)
+	data.Set("client_assertion_type", jwtBearerAssertionType)
 	data.Set("client_assertion", token)
 	reqBody := strings.NewReader(data.Encode())
 	r, err := http.NewRequest(http.MethodPost, c.tokenEndpoint, reqBody)
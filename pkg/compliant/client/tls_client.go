@@ -23,10 +23,10 @@ func (c tlsClient) Id() string {
 	return c.id
 }
 
-func (c tlsClient) CredentialsGrantRequest() (*http.Request, error) {
+func (c tlsClient) CredentialsGrantRequest(scope string) (*http.Request, error) {
 	data := url.Values{}
 	data.Set("client_id", c.id)
-	data.Set("scope", "")
+	data.Set("scope", scope)
 	data.Set("grant_type", "client_credentials")
 	reqBody := strings.NewReader(data.Encode())
 	r, err := http.NewRequest(http.MethodPost, c.tokenEndpoint, reqBody)
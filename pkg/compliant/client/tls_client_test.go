@@ -11,7 +11,7 @@ import (
 func TestClientTlsClientAuth(t *testing.T) {
 	client := NewTlsClientAuth("id", "token")
 
-	request, err := client.CredentialsGrantRequest()
+	request, err := client.CredentialsGrantRequest("accounts payments")
 	require.NoError(t, err)
 	assert.Equal(t, "id", client.Id())
 	bodyByes, err := ioutil.ReadAll(request.Body)
@@ -25,4 +25,6 @@ func TestClientTlsClientAuth(t *testing.T) {
 	require.Equal(t, 1, len(bodyDecoded["grant_type"]))
 	require.Equal(t, "client_credentials", bodyDecoded["grant_type"][0])
 
+	assert.Equal(t, "accounts payments", bodyDecoded.Get("scope"))
+
 }
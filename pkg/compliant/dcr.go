@@ -1,6 +1,11 @@
 package compliant
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/pkg/errors"
+)
 
 func IsSupportedSpecVersion(version string) bool {
 	return version == "3.2" || version == "3.3"
@@ -15,3 +20,38 @@ func NewSpecManifest(version string, cfg DCR32Config) (Manifest, error) {
 	}
 	return nil, errors.New("specification version  not supported")
 }
+
+// NewAuthMethodsManifest adds to a manifest the registration lifecycle scenarios (create, delete, retrieve, update)
+// and the client credentials grant scenarios, including the invalid client credentials grants,
+// once per token endpoint auth method advertised by the ASPSP, instead of the preferred one only
+func NewAuthMethodsManifest(manifest Manifest, cfg DCR32Config) (Manifest, error) {
+	methods := auth.SupportedAuthMethods(cfg.OpenIDConfig)
+	if len(methods) == 0 {
+		return nil, errors.New("no supported token endpoint auth method advertised")
+	}
+
+	secureClient := cfg.SecureClient
+	validator := cfg.SchemaValidator
+	scenarios := append(Scenarios{}, manifest.Scenarios()...)
+	for _, method := range methods {
+		authoriserBuilder := cfg.AuthoriserBuilder.WithAuthMethod(method)
+		methodScenarios := Scenarios{
+			DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
+			DCR32DeleteSoftwareClient(cfg, secureClient, authoriserBuilder),
+			DCR32RetrieveSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
+			DCR32UpdateSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
+			DCR32UpdateSoftwareClientPersisted(cfg, secureClient, authoriserBuilder),
+			DCR32ClientCredentialsGrantResponse(cfg, secureClient, authoriserBuilder),
+			DCR32InvalidClientCredentialsGrant(cfg, secureClient, authoriserBuilder),
+		}
+		for _, scenario := range methodScenarios {
+			scenarios = append(scenarios, NewAuthMethodScenario(scenario, method))
+		}
+	}
+
+	return NewManifest(
+		fmt.Sprintf("%s (all auth methods)", manifest.Name()),
+		manifest.Version(),
+		scenarios,
+	)
+}
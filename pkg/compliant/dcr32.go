@@ -1,6 +1,7 @@
 package compliant
 
 import (
+	"crypto/rsa"
 	"fmt"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 	"github.com/dgrijalva/jwt-go"
@@ -8,6 +9,8 @@ import (
 	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
 )
 
@@ -17,6 +20,7 @@ const (
 	specLinkRegisterSoftware = "https://openbanking.atlassian.net/wiki/spaces/DZ/pages/1078034771/Dynamic+Client+Registration+-+v3.2#DynamicClientRegistration-v3.2-POST/register"
 	specLinkDeleteSoftware   = "https://openbanking.atlassian.net/wiki/spaces/DZ/pages/1078034771/Dynamic+Client+Registration+-+v3.2#DynamicClientRegistration-v3.2-DELETE/register/{ClientId}"
 	specLinkRetrieveSoftware = "https://openbanking.atlassian.net/wiki/spaces/DZ/pages/1078034771/Dynamic+Client+Registration+-+v3.2#DynamicClientRegistration-v3.2-GET/register/{ClientId}"
+	specLinkFAPITLS          = "https://openid.net/specs/openid-financial-api-part-2-1_0.html#tls-and-dnssec-considerations"
 	specLinkUpdateSoftware   = "https://openbanking.atlassian.net/wiki/spaces/DZ/pages/1078034771/Dynamic+Client+Registration+-+v3.2#DynamicClientRegistration-v3.2-PUT/register/{ClientId}"
 )
 
@@ -27,24 +31,45 @@ func NewDCR32(cfg DCR32Config) (Manifest, error) {
 
 	scenarios := Scenarios{
 		DCR32ValidateOIDCConfigRegistrationURL(cfg),
-		DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder),
+		DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32DeleteSoftwareClient(cfg, secureClient, authoriserBuilder),
 		DCR32CreateInvalidRegistrationRequest(cfg, secureClient, authoriserBuilder),
 		DCR32RetrieveSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32RetrieveWithInvalidCredentials(cfg, secureClient, authoriserBuilder),
-		DCR32UpdateSoftwareClient(cfg, secureClient, authoriserBuilder),
+		DCR32UpdateSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32UpdateSoftwareClientWithWrongId(cfg, secureClient, authoriserBuilder),
 		DCR32RetrieveSoftwareClientWrongId(cfg, secureClient, authoriserBuilder),
 		DCR32RegisterSoftwareWrongResponseType(cfg, secureClient, authoriserBuilder),
+		DCR32UpdateSoftwareClientPersisted(cfg, secureClient, authoriserBuilder),
+		DCR32ValidateOIDCDiscoveryDocument(cfg),
+		DCR32ClientSecretSentInWrongPlace(cfg, secureClient, authoriserBuilder),
+		DCR32ClientCredentialsGrantCertificateBound(cfg, secureClient, authoriserBuilder),
+		DCR32ClientCredentialsGrantResponse(cfg, secureClient, authoriserBuilder),
+		DCR32InvalidClientCredentialsGrant(cfg, secureClient, authoriserBuilder),
+		DCR32ClientCredentialsGrantUnregisteredScope(cfg, secureClient, authoriserBuilder),
+		DCR32ValidateRegistrationEndpointTLS(cfg, secureClient),
+		DCR32RegisterWithInvalidTransportCert(cfg, authoriserBuilder),
+		DCR32ClientCredentialsGrantWithInvalidTransportCert(cfg, secureClient, authoriserBuilder),
 	}
 
 	return NewManifest("DCR32", "1.0", scenarios)
 }
 
 func DCR32ValidateOIDCConfigRegistrationURL(cfg DCR32Config) Scenario {
+	id := "DCR-001"
+	name := "Validate OIDC Config Registration URL"
+
+	if cfg.DiscoveryOverridden {
+		return NewBuilder(
+			id,
+			fmt.Sprintf("(SKIP discovery document read from file or overridden) %s", name),
+			specLinkDiscovery,
+		).Build()
+	}
+
 	return NewBuilder(
-		"DCR-001",
-		"Validate OIDC Config Registration URL",
+		id,
+		name,
 		specLinkDiscovery,
 	).TestCase(
 		NewTestCaseBuilder("Validate Registration URL").
@@ -57,33 +82,45 @@ func DCR32CreateSoftwareClient(
 	cfg DCR32Config,
 	secureClient *http.Client,
 	authoriserBuilder auth.AuthoriserBuilder,
+	validator schema.Validator,
 ) Scenario {
 	return NewBuilder(
 		"DCR-002",
 		"Dynamically create a new software client",
 		specLinkRegisterSoftware,
 	).
-		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, validator)...).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
 		Build()
 }
 
+// DCR32CreateSoftwareClientTestCases registers a software client and retrieves a client credentials grant,
+// when validator is not nil the registration response is also validated against the schema and the request
 func DCR32CreateSoftwareClientTestCases(
 	cfg DCR32Config,
 	secureClient *http.Client,
 	authoriserBuilder auth.AuthoriserBuilder,
+	validator schema.Validator,
 ) []TestCase {
+	registerTestCase := NewTestCaseBuilder("Register software client").
+		WithHttpClient(secureClient).
+		GenerateSignedClaims(authoriserBuilder).
+		PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
+		AssertStatusCodeCreated()
+	if validator != nil {
+		registerTestCase = registerTestCase.
+			AssertInteractionIDEchoed().
+			AssertValidSchemaResponse(validator).
+			AssertRegisteredMetadataMatchesRequest()
+	}
+
 	return []TestCase{
-		NewTestCaseBuilder("Register software client").
-			WithHttpClient(secureClient).
-			GenerateSignedClaims(authoriserBuilder).
-			PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-			AssertStatusCodeCreated().
+		registerTestCase.
 			ParseClientRegisterResponse(authoriserBuilder).
 			Build(),
 		NewTestCaseBuilder("Retrieve client credentials grant").
-			WithHttpClient(secureClient).
-			GetClientCredentialsGrant(cfg.OpenIDConfig.TokenEndpoint).
+			WithHttpClient(tokenEndpointClient(cfg, secureClient, authoriserBuilder)).
+			GetClientCredentialsGrant(cfg.OpenIDConfig.TokenEndpoint, cfg.Scopes).
 			Build(),
 	}
 }
@@ -126,7 +163,7 @@ func DCR32DeleteSoftwareClient(
 		name,
 		specLinkDeleteSoftware,
 	).
-		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
 		TestCase(
 			NewTestCaseBuilder("Retrieve delete software client should fail").
@@ -142,7 +179,7 @@ func DCR32CreateInvalidRegistrationRequest(
 	secureClient *http.Client,
 	authoriserBuilder auth.AuthoriserBuilder,
 ) Scenario {
-	return NewBuilder(
+	builder := NewBuilder(
 		"DCR-004",
 		"Dynamically create a new software client will fail on invalid registration request",
 		specLinkRegisterSoftware,
@@ -190,15 +227,21 @@ func DCR32CreateInvalidRegistrationRequest(
 				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 				AssertStatusCodeBadRequest().
 				Build(),
-		).
-		TestCase(
+		)
+
+	// RS256 can only be signed with a RSA signing key
+	if _, ok := cfg.PrivateKey.(*rsa.PrivateKey); ok {
+		builder = builder.TestCase(
 			NewTestCaseBuilder("Register software client will fail with token endpoint auth method RS256").
 				WithHttpClient(secureClient).
 				GenerateSignedClaims(authoriserBuilder.WithTokenEndpointAuthMethod(jwt.SigningMethodRS256)).
 				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 				AssertStatusCodeBadRequest().
 				Build(),
-		).Build()
+		)
+	}
+
+	return builder.Build()
 }
 
 func DCR32RetrieveSoftwareClient(
@@ -212,7 +255,7 @@ func DCR32RetrieveSoftwareClient(
 		"Dynamically retrieve a new software client",
 		specLinkRetrieveSoftware,
 	).
-		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
 		TestCase(DCR32RetrieveSoftwareClientTestCase(cfg, secureClient, validator)).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
 		Build()
@@ -268,8 +311,8 @@ func DCR32RetrieveWithInvalidCredentials(
 		).
 		TestCase(
 			NewTestCaseBuilder("Retrieve client credentials grant").
-				WithHttpClient(secureClient).
-				GetClientCredentialsGrant(cfg.OpenIDConfig.TokenEndpoint).
+				WithHttpClient(tokenEndpointClient(cfg, secureClient, authoriserBuilder)).
+				GetClientCredentialsGrant(cfg.OpenIDConfig.TokenEndpoint, cfg.Scopes).
 				Build(),
 		).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
@@ -280,6 +323,7 @@ func DCR32UpdateSoftwareClient(
 	cfg DCR32Config,
 	secureClient *http.Client,
 	authoriserBuilder auth.AuthoriserBuilder,
+	validator schema.Validator,
 ) Scenario {
 	id := "DCR-008"
 	const name = "I should be able update a registered software"
@@ -297,13 +341,14 @@ func DCR32UpdateSoftwareClient(
 		name,
 		specLinkUpdateSoftware,
 	).
-		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
 		TestCase(
 			NewTestCaseBuilder("Update an existing software client").
 				WithHttpClient(secureClient).
 				GenerateSignedClaims(authoriserBuilder).
 				ClientUpdate(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 				AssertStatusCodeOk().
+				AssertValidSchemaResponse(validator).
 				Build(),
 		).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
@@ -331,7 +376,7 @@ func DCR32UpdateSoftwareClientWithWrongId(
 		name,
 		specLinkUpdateSoftware,
 	).
-		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
 		TestCase(
 			NewTestCaseBuilder("Update a deleted software client").
@@ -356,7 +401,7 @@ func DCR32RetrieveSoftwareClientWrongId(
 		name,
 		specLinkUpdateSoftware,
 	).
-		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
 		TestCase(
 			NewTestCaseBuilder("Retrieve a deleted software client").
@@ -393,3 +438,366 @@ func DCR32RegisterSoftwareWrongResponseType(
 		).
 		Build()
 }
+
+func DCR32UpdateSoftwareClientPersisted(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Scenario {
+	id := "DCR-012"
+	const name = "When I update a registered software the changes should be returned when retrieving it"
+
+	if !cfg.PutImplemented || !cfg.GetImplemented {
+		return NewBuilder(
+			id,
+			fmt.Sprintf("(SKIP PUT or GET endpoint not implemented) %s", name),
+			specLinkUpdateSoftware,
+		).Build()
+	}
+
+	return NewBuilder(
+		id,
+		name,
+		specLinkUpdateSoftware,
+	).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
+		TestCase(
+			NewTestCaseBuilder("Update software client metadata").
+				WithHttpClient(secureClient).
+				GenerateSignedClaims(updatedMetadataAuthoriserBuilder(cfg, authoriserBuilder)).
+				ClientUpdate(cfg.OpenIDConfig.RegistrationEndpointAsString()).
+				AssertStatusCodeOk().
+				Build(),
+		).
+		TestCase(
+			NewTestCaseBuilder("Retrieve updated software client").
+				WithHttpClient(secureClient).
+				ClientRetrieve(cfg.OpenIDConfig.RegistrationEndpointAsString()).
+				AssertStatusCodeOk().
+				AssertClientUpdatePersisted(updatesScope(cfg)).
+				Build(),
+		).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Build()
+}
+
+// updatedMetadataAuthoriserBuilder changes a mutable client metadata field for an update request,
+// redirect uris are narrowed down to the first one if more are configured, otherwise scope is reduced to openid
+func updatedMetadataAuthoriserBuilder(
+	cfg DCR32Config,
+	authoriserBuilder auth.AuthoriserBuilder,
+) auth.AuthoriserBuilder {
+	if !updatesScope(cfg) {
+		return authoriserBuilder.WithRedirectURIs(cfg.RedirectURIs[:1])
+	}
+	return authoriserBuilder.WithScope("openid")
+}
+
+// updatesScope is true when scope is the only field the update request changes, so it must be persisted
+func updatesScope(cfg DCR32Config) bool {
+	return len(cfg.RedirectURIs) <= 1
+}
+
+func DCR32ValidateOIDCDiscoveryDocument(cfg DCR32Config) Scenario {
+	id := "DCR-013"
+	name := "Validate OIDC discovery document"
+
+	if cfg.DiscoveryOverridden {
+		return NewBuilder(
+			id,
+			fmt.Sprintf("(SKIP discovery document read from file or overridden) %s", name),
+			specLinkDiscovery,
+		).Build()
+	}
+
+	return NewBuilder(
+		id,
+		name,
+		specLinkDiscovery,
+	).TestCase(
+		NewTestCaseBuilder("Validate discovery metadata").
+			ValidateOpenIDConfig(cfg.OpenIDConfig, cfg.WellknownEndpoint).
+			Build(),
+	).Build()
+}
+
+func DCR32ClientSecretSentInWrongPlace(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Scenario {
+	id := "DCR-014"
+	const name = "When I send the client secret using a different auth method than registered it should be rejected"
+
+	authMethod := clientSecretAuthMethod(cfg.OpenIDConfig)
+	if authMethod == "" {
+		return NewBuilder(
+			id,
+			fmt.Sprintf("(SKIP client_secret_basic or client_secret_post not supported) %s", name),
+			specLinkRegisterSoftware,
+		).Build()
+	}
+
+	authoriserBuilder = authoriserBuilder.WithAuthMethod(authMethod)
+	return NewBuilder(
+		id,
+		name,
+		specLinkRegisterSoftware,
+	).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
+		TestCase(
+			NewTestCaseBuilder("Client credentials grant with misplaced client secret").
+				WithHttpClient(secureClient).
+				AssertMisplacedClientSecretRejected().
+				Build(),
+		).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Build()
+}
+
+func DCR32ClientCredentialsGrantCertificateBound(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Scenario {
+	// the token is bound to the certificate used at the token endpoint, any other certificate must be rejected
+	boundCert, otherClient := cfg.TransportCert, cfg.SelfSignedClient
+	if authoriserBuilder.AuthMethod() == "self_signed_tls_client_auth" {
+		boundCert, otherClient = cfg.SelfSignedCert, secureClient
+	}
+
+	boundTokenTestCase := NewTestCaseBuilder("Validate client credentials grant access token is certificate bound").
+		WithHttpClient(tokenEndpointClient(cfg, secureClient, authoriserBuilder)).
+		AssertCertificateBoundToken(cfg.OpenIDConfig.IntrospectionEndpoint, boundCert)
+	if cfg.GetImplemented && otherClient != nil {
+		boundTokenTestCase = boundTokenTestCase.
+			WithHttpClient(otherClient).
+			AssertBoundTokenRejected(cfg.OpenIDConfig.RegistrationEndpointAsString())
+	}
+
+	return NewBuilder(
+		"DCR-015",
+		"Client credentials grant access token should be bound to the transport certificate",
+		specLinkRegisterSoftware,
+	).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
+		TestCase(boundTokenTestCase.Build()).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Build()
+}
+
+func DCR32ClientCredentialsGrantResponse(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Scenario {
+	return NewBuilder(
+		"DCR-016",
+		"Client credentials grant token response should be a valid Bearer token response",
+		specLinkRegisterSoftware,
+	).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
+		TestCase(
+			NewTestCaseBuilder("Validate client credentials grant token response").
+				WithHttpClient(tokenEndpointClient(cfg, secureClient, authoriserBuilder)).
+				AssertValidClientCredentialsGrantResponse().
+				Build(),
+		).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Build()
+}
+
+func DCR32InvalidClientCredentialsGrant(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Scenario {
+	invalidGrants := []client.InvalidGrant{client.UnregisteredGrantType}
+	// client assertions are only sent by private_key_jwt and client_secret_jwt
+	authMethod := authoriserBuilder.AuthMethod()
+	if authMethod == "private_key_jwt" || authMethod == "client_secret_jwt" {
+		invalidGrants = append(
+			invalidGrants,
+			client.WrongAssertionAudience,
+			client.ExpiredAssertion,
+			client.ReusedAssertionJti,
+			client.WrongAssertionType,
+		)
+	}
+
+	invalidGrantTestCase := NewTestCaseBuilder("Client credentials grant with invalid requests").
+		WithHttpClient(tokenEndpointClient(cfg, secureClient, authoriserBuilder))
+	for _, invalid := range invalidGrants {
+		invalidGrantTestCase.AssertInvalidClientCredentialsGrantRejected(invalid)
+	}
+
+	return NewBuilder(
+		"DCR-017",
+		"When I send an invalid client credentials grant request it should be rejected",
+		specLinkRegisterSoftware,
+	).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
+		TestCase(invalidGrantTestCase.Build()).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Build()
+}
+
+func DCR32ClientCredentialsGrantUnregisteredScope(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Scenario {
+	id := "DCR-018"
+	const name = "When I request a scope outside the registration in a client credentials grant it should be rejected"
+
+	scope := unregisteredScope(cfg.Scopes)
+	if scope == "" {
+		return NewBuilder(
+			id,
+			fmt.Sprintf("(SKIP no scope outside the registration) %s", name),
+			specLinkRegisterSoftware,
+		).Build()
+	}
+
+	return NewBuilder(
+		id,
+		name,
+		specLinkRegisterSoftware,
+	).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
+		TestCase(
+			NewTestCaseBuilder("Client credentials grant with scope outside the registration").
+				WithHttpClient(tokenEndpointClient(cfg, secureClient, authoriserBuilder)).
+				AssertUnregisteredScopeRejected(scope).
+				Build(),
+		).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Build()
+}
+
+// DCR32ValidateRegistrationEndpointTLS checks the registration endpoint TLS handshake with the transport certificate,
+// then probes that connections without a client certificate or with non FAPI cipher suites are refused
+func DCR32ValidateRegistrationEndpointTLS(cfg DCR32Config, secureClient *http.Client) Scenario {
+	registrationEndpoint := cfg.OpenIDConfig.RegistrationEndpointAsString()
+	return NewBuilder(
+		"DCR-019",
+		"Registration endpoint TLS configuration should be FAPI compliant",
+		specLinkFAPITLS,
+	).
+		TestCase(
+			NewTestCaseBuilder("Validate registration endpoint TLS handshake").
+				WithHttpClient(secureClient).
+				GetNamed("GET registration endpoint", registrationEndpoint).
+				AssertFAPITLS().
+				AssertServerCertNotExpiring(cfg.ServerCertExpiryDays).
+				Build(),
+		).
+		TestCase(
+			NewTestCaseBuilder("Validate registration endpoint refuses non compliant TLS clients").
+				WithHttpClient(cfg.NoClientCertClient).
+				AssertClientCertRequired(registrationEndpoint).
+				WithHttpClient(cfg.NonFAPICipherClient).
+				AssertNonFAPICipherSuitesRejected(registrationEndpoint).
+				Build(),
+		).
+		Build()
+}
+
+// invalidCertClient is an http client with a transport certificate the ASPSP must refuse
+type invalidCertClient struct {
+	description string
+	client      *http.Client
+}
+
+// invalidCertClients the expired certificate is also self signed, so the ASPSP may refuse it as untrusted
+// without checking its validity period, the scenarios only assert an invalid certificate is rejected
+func invalidCertClients(cfg DCR32Config) []invalidCertClient {
+	return []invalidCertClient{
+		{"without client certificate", cfg.NoClientCertClient},
+		{"with a certificate from an untrusted CA", cfg.UntrustedCAClient},
+		{"with an expired certificate", cfg.ExpiredCertClient},
+	}
+}
+
+func DCR32RegisterWithInvalidTransportCert(cfg DCR32Config, authoriserBuilder auth.AuthoriserBuilder) Scenario {
+	registrationEndpoint := cfg.OpenIDConfig.RegistrationEndpointAsString()
+	scenarioBuilder := NewBuilder(
+		"DCR-020",
+		"Registering without a valid transport certificate should be rejected",
+		specLinkRegisterSoftware,
+	)
+	// a registration request per certificate so the ASPSP can't reject it as a replay
+	for _, invalidCert := range invalidCertClients(cfg) {
+		scenarioBuilder = scenarioBuilder.TestCase(
+			NewTestCaseBuilder(fmt.Sprintf("Register software client %s", invalidCert.description)).
+				WithHttpClient(invalidCert.client).
+				GenerateSignedClaims(authoriserBuilder).
+				AssertInvalidCertRegisterRejected(registrationEndpoint, invalidCert.description).
+				Build(),
+		)
+	}
+	return scenarioBuilder.Build()
+}
+
+func DCR32ClientCredentialsGrantWithInvalidTransportCert(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Scenario {
+	invalidGrantTestCase := NewTestCaseBuilder("Client credentials grant with invalid transport certificates")
+	for _, invalidCert := range invalidCertClients(cfg) {
+		invalidGrantTestCase = invalidGrantTestCase.
+			WithHttpClient(invalidCert.client).
+			AssertInvalidCertGrantRejected(invalidCert.description)
+	}
+
+	return NewBuilder(
+		"DCR-021",
+		"Client credentials grant without a valid transport certificate should be rejected",
+		specLinkRegisterSoftware,
+	).
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder, nil)...).
+		TestCase(invalidGrantTestCase.Build()).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Build()
+}
+
+// unregisteredScope returns an SSA role scope that is not registered, the registration scope defaults to accounts
+func unregisteredScope(scopes []string) string {
+	if len(scopes) == 0 {
+		scopes = []string{"accounts"}
+	}
+	registered := map[string]bool{}
+	for _, scope := range scopes {
+		registered[scope] = true
+	}
+	for _, scope := range []string{"accounts", "payments", "fundsconfirmations"} {
+		if !registered[scope] {
+			return scope
+		}
+	}
+	return ""
+}
+
+// clientSecretAuthMethod returns the first client secret based auth method advertised by the ASPSP
+func clientSecretAuthMethod(config openid.Configuration) string {
+	for _, method := range auth.SupportedAuthMethods(config) {
+		if method == "client_secret_basic" || method == "client_secret_post" {
+			return method
+		}
+	}
+	return ""
+}
+
+// tokenEndpointClient returns the http client used to authenticate at the token endpoint,
+// self_signed_tls_client_auth uses the self signed certificate registered in the client jwks
+func tokenEndpointClient(
+	cfg DCR32Config,
+	secureClient *http.Client,
+	authoriserBuilder auth.AuthoriserBuilder,
+) *http.Client {
+	if authoriserBuilder.AuthMethod() == "self_signed_tls_client_auth" && cfg.SelfSignedClient != nil {
+		return cfg.SelfSignedClient
+	}
+	return secureClient
+}
@@ -1,38 +1,63 @@
 package compliant
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"encoding/pem"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/pkg/errors"
+	"io"
 	http2 "net/http"
+	"strings"
+	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 )
 
+const defaultServerCertExpiryDays = 30
+
 type DCR32Config struct {
 	OpenIDConfig       openid.Configuration
+	WellknownEndpoint  string
 	SSA                string
 	KID                string
 	RedirectURIs       []string
+	Scopes             []string
 	TokenSigningMethod jwt.SigningMethod
-	PrivateKey         *rsa.PrivateKey
+	PrivateKey         crypto.Signer
+	TransportCert      *x509.Certificate
+	SelfSignedCert     *x509.Certificate
 	SecureClient       *http2.Client
-	GetImplemented     bool
-	PutImplemented     bool
-	DeleteImplemented  bool
-	AuthoriserBuilder  auth.AuthoriserBuilder
-	SchemaValidator    schema.Validator
+	SelfSignedClient   *http2.Client
+	// NoClientCertClient and NonFAPICipherClient probe the ASPSP TLS configuration, they must be refused
+	NoClientCertClient  *http2.Client
+	NonFAPICipherClient *http2.Client
+	// UntrustedCAClient and ExpiredCertClient present generated transport certificates the ASPSP must refuse
+	UntrustedCAClient *http2.Client
+	ExpiredCertClient *http2.Client
+	// ServerCertExpiryDays is the minimum number of days before the ASPSP server certificates expire
+	ServerCertExpiryDays int
+	GetImplemented       bool
+	PutImplemented       bool
+	DeleteImplemented    bool
+	AuthoriserBuilder    auth.AuthoriserBuilder
+	SchemaValidator      schema.Validator
+	// DiscoveryOverridden is true when the discovery document wasn't fetched as published by the ASPSP,
+	// read from a file or with overridden metadata, so it can't be validated
+	DiscoveryOverridden bool
 }
 
 func NewDCR32Config(
 	openIDConfig openid.Configuration,
+	discoveryOverridden bool,
+	wellknownEndpoint string,
 	ssa, aud, kid, issuer string,
 	redirectURIs []string,
+	scopes []string,
 	signingKeyPEM string,
 	transportSigningKeyPEM string,
 	transportCertPEM string,
@@ -42,9 +67,13 @@ func NewDCR32Config(
 	putImplemented bool,
 	deleteImplemented bool,
 	tlsSkipVerify bool,
+	network http.NetworkConfig,
+	recorder *http.Recorder,
+	keyLogWriter io.Writer,
+	serverCertExpiryDays int,
 	specVersion string,
 ) (DCR32Config, error) {
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(signingKeyPEM))
+	privateKey, err := certs.ParseSigningKeyFromPEM([]byte(signingKeyPEM))
 	if err != nil {
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
@@ -59,7 +88,7 @@ func NewDCR32Config(
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
 
-	tokenSignMethod, err := responseTokenSignMethod(openIDConfig.TokenEndpointSigningAlgSupported)
+	tokenSignMethod, err := responseTokenSignMethod(openIDConfig.TokenEndpointSigningAlgSupported, privateKey)
 	if err != nil {
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
@@ -69,6 +98,10 @@ func NewDCR32Config(
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
 
+	if len(scopes) == 0 {
+		scopes = ssaScopes(ssa)
+	}
+
 	// default authoriser
 	authoriserBuilder := auth.NewAuthoriserBuilder().
 		WithOpenIDConfig(openIDConfig).
@@ -82,31 +115,147 @@ func NewDCR32Config(
 		WithTokenEndpointAuthMethod(tokenSignMethod).
 		WithTransportCert(transportCert).
 		WithTransportCertSubjectDn(transportCertSubjectDn)
+	if len(scopes) > 0 {
+		authoriserBuilder = authoriserBuilder.WithScope(strings.Join(append(append([]string{}, scopes...), "openid"), " "))
+	}
 
 	secureClient, err := http.NewBuilder().
 		WithRootCAs(transportRootCAs).
 		WithTransportKeyPair(transportCertPEM, transportSigningKeyPEM).
 		WithTlsSkipVerify(tlsSkipVerify).
+		WithNetworkConfig(network).
+		WithRecorder(recorder).
+		WithKeyLogWriter(keyLogWriter).
 		Build()
 	if err != nil {
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
 
+	// self_signed_tls_client_auth authenticates at the token endpoint with a self signed certificate,
+	// only generated when the ASPSP advertises the method
+	var selfSignedCert *x509.Certificate
+	var selfSignedClient *http2.Client
+	if advertisesAuthMethod(openIDConfig, "self_signed_tls_client_auth") {
+		selfSigned, err := certs.NewSelfSigned(transportCert.Subject, 24*time.Hour)
+		if err != nil {
+			return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+		}
+		authoriserBuilder = authoriserBuilder.WithSelfSignedCert(selfSigned.Cert)
+
+		selfSignedCert = selfSigned.Cert
+		selfSignedClient, err = http.NewBuilder().
+			WithRootCAs(transportRootCAs).
+			WithTransportKeyPair(selfSigned.CertPEM, selfSigned.KeyPEM).
+			WithTlsSkipVerify(tlsSkipVerify).
+			WithNetworkConfig(network).
+			WithRecorder(recorder).
+			WithKeyLogWriter(keyLogWriter).
+			Build()
+		if err != nil {
+			return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+		}
+	}
+
+	noClientCertClient, err := http.NewBuilder().
+		WithoutClientCert().
+		WithRootCAs(transportRootCAs).
+		WithTlsSkipVerify(tlsSkipVerify).
+		WithNetworkConfig(network).
+		WithRecorder(recorder).
+		WithKeyLogWriter(keyLogWriter).
+		Build()
+	if err != nil {
+		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+	}
+
+	nonFAPICipherClient, err := http.NewBuilder().
+		WithRootCAs(transportRootCAs).
+		WithTransportKeyPair(transportCertPEM, transportSigningKeyPEM).
+		WithCipherSuites(http.NonFAPICipherSuites()).
+		WithTlsSkipVerify(tlsSkipVerify).
+		WithNetworkConfig(network).
+		WithRecorder(recorder).
+		WithKeyLogWriter(keyLogWriter).
+		Build()
+	if err != nil {
+		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+	}
+
+	untrustedCA, err := certs.NewUntrustedCASigned(transportCert.Subject, 24*time.Hour)
+	if err != nil {
+		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+	}
+
+	untrustedCAClient, err := http.NewBuilder().
+		WithRootCAs(transportRootCAs).
+		WithTransportKeyPair(untrustedCA.CertPEM, untrustedCA.KeyPEM).
+		WithTlsSkipVerify(tlsSkipVerify).
+		WithNetworkConfig(network).
+		WithRecorder(recorder).
+		WithKeyLogWriter(keyLogWriter).
+		Build()
+	if err != nil {
+		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+	}
+
+	expired, err := certs.NewExpiredSelfSigned(transportCert.Subject)
+	if err != nil {
+		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+	}
+
+	expiredCertClient, err := http.NewBuilder().
+		WithRootCAs(transportRootCAs).
+		WithTransportKeyPair(expired.CertPEM, expired.KeyPEM).
+		WithTlsSkipVerify(tlsSkipVerify).
+		WithNetworkConfig(network).
+		WithRecorder(recorder).
+		WithKeyLogWriter(keyLogWriter).
+		Build()
+	if err != nil {
+		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
+	}
+
+	if serverCertExpiryDays <= 0 {
+		serverCertExpiryDays = defaultServerCertExpiryDays
+	}
+
 	return DCR32Config{
-		OpenIDConfig:      openIDConfig,
-		SSA:               ssa,
-		KID:               kid,
-		RedirectURIs:      redirectURIs,
-		PrivateKey:        privateKey,
-		SecureClient:      secureClient,
-		GetImplemented:    getImplemented,
-		PutImplemented:    putImplemented,
-		DeleteImplemented: deleteImplemented,
-		AuthoriserBuilder: authoriserBuilder,
-		SchemaValidator:   schemaValidator,
+		OpenIDConfig:         openIDConfig,
+		DiscoveryOverridden:  discoveryOverridden,
+		WellknownEndpoint:    wellknownEndpoint,
+		SSA:                  ssa,
+		KID:                  kid,
+		RedirectURIs:         redirectURIs,
+		Scopes:               scopes,
+		TokenSigningMethod:   tokenSignMethod,
+		PrivateKey:           privateKey,
+		TransportCert:        transportCert,
+		SelfSignedCert:       selfSignedCert,
+		SecureClient:         secureClient,
+		SelfSignedClient:     selfSignedClient,
+		NoClientCertClient:   noClientCertClient,
+		NonFAPICipherClient:  nonFAPICipherClient,
+		UntrustedCAClient:    untrustedCAClient,
+		ExpiredCertClient:    expiredCertClient,
+		ServerCertExpiryDays: serverCertExpiryDays,
+		GetImplemented:       getImplemented,
+		PutImplemented:       putImplemented,
+		DeleteImplemented:    deleteImplemented,
+		AuthoriserBuilder:    authoriserBuilder,
+		SchemaValidator:      schemaValidator,
 	}, nil
 }
 
+// advertisesAuthMethod checks if the ASPSP advertises a token endpoint auth method
+func advertisesAuthMethod(config openid.Configuration, method string) bool {
+	for _, supported := range config.TokenEndpointAuthMethodsSupported {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
 func certificate(transportCertPEM string) (*x509.Certificate, error) {
 	block, _ := pem.Decode([]byte(transportCertPEM))
 	if block == nil {
@@ -118,3 +267,36 @@ func certificate(transportCertPEM string) (*x509.Certificate, error) {
 	}
 	return cert, nil
 }
+
+// ssaScopes returns the scopes allowed by the SSA software_roles,
+// the SSA is not verified as it's only read to pick the scopes to request
+func ssaScopes(ssa string) []string {
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(ssa, claims); err != nil {
+		return nil
+	}
+	roles, ok := claims["software_roles"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var scopes []string
+	for _, role := range roles {
+		if scope := ssaRoleScope(role); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// ssaRoleScope returns the client credentials grant scope of an Open Banking SSA software role
+func ssaRoleScope(role interface{}) string {
+	switch role {
+	case "AISP":
+		return "accounts"
+	case "PISP":
+		return "payments"
+	case "CBPII":
+		return "fundsconfirmations"
+	}
+	return ""
+}
@@ -1,7 +1,10 @@
 package compliant
 
 import (
+	"crypto/ecdsa"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
@@ -20,11 +23,14 @@ func TestNewDCR32Config(t *testing.T) {
 
 	config, err := NewDCR32Config(
 		openid.Configuration{},
+		false,
+		"https://issuer/.well-known/openid-configuration",
 		"ssa",
 		"aud",
 		"kid",
 		"ssaId",
 		[]string{"/redirect"},
+		nil,
 		string(privateKeyPEM),
 		string(privateKeyPEM),
 		string(certPEM),
@@ -34,15 +40,117 @@ func TestNewDCR32Config(t *testing.T) {
 		false,
 		false,
 		false,
+		http.NetworkConfig{},
+		nil,
+		nil,
+		0,
 		"3.2",
 	)
 	require.NoError(t, err)
 
 	assert.Equal(t, openid.Configuration{}, config.OpenIDConfig)
+	assert.False(t, config.DiscoveryOverridden)
+	assert.Equal(t, "https://issuer/.well-known/openid-configuration", config.WellknownEndpoint)
 	assert.Equal(t, "ssa", config.SSA)
 	assert.Equal(t, "kid", config.KID)
 	assert.Equal(t, []string{"/redirect"}, config.RedirectURIs)
 	assert.True(t, config.GetImplemented)
 	assert.False(t, config.PutImplemented)
 	assert.False(t, config.DeleteImplemented)
+	assert.NotNil(t, config.SecureClient)
+	assert.Nil(t, config.SelfSignedClient)
+	assert.Nil(t, config.SelfSignedCert)
+	assert.NotNil(t, config.NoClientCertClient)
+	assert.NotNil(t, config.NonFAPICipherClient)
+	assert.NotNil(t, config.UntrustedCAClient)
+	assert.NotNil(t, config.ExpiredCertClient)
+	assert.Equal(t, 30, config.ServerCertExpiryDays)
+}
+
+func TestNewDCR32Config_SelfSignedClientWhenAdvertised(t *testing.T) {
+	privateKeyPEM, err := ioutil.ReadFile("testdata/client-sample-key.key")
+	require.NoError(t, err)
+
+	certPEM, err := ioutil.ReadFile("testdata/client-sample-cert.pem")
+	require.NoError(t, err)
+
+	config, err := NewDCR32Config(
+		openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"self_signed_tls_client_auth"}},
+		false,
+		"https://issuer/.well-known/openid-configuration",
+		"ssa",
+		"aud",
+		"kid",
+		"ssaId",
+		[]string{"/redirect"},
+		nil,
+		string(privateKeyPEM),
+		string(privateKeyPEM),
+		string(certPEM),
+		"",
+		[]string{},
+		true,
+		false,
+		false,
+		false,
+		http.NetworkConfig{},
+		nil,
+		nil,
+		0,
+		"3.2",
+	)
+	require.NoError(t, err)
+
+	assert.NotNil(t, config.SelfSignedClient)
+	assert.NotNil(t, config.SelfSignedCert)
+}
+
+func TestNewDCR32Config_ECSigningKey(t *testing.T) {
+	signingKeyPEM, err := ioutil.ReadFile("testdata/client-sample-ec-key.key")
+	require.NoError(t, err)
+
+	privateKeyPEM, err := ioutil.ReadFile("testdata/client-sample-key.key")
+	require.NoError(t, err)
+
+	certPEM, err := ioutil.ReadFile("testdata/client-sample-cert.pem")
+	require.NoError(t, err)
+
+	config, err := NewDCR32Config(
+		openid.Configuration{TokenEndpointSigningAlgSupported: &[]string{"PS256", "ES256"}},
+		false,
+		"https://issuer/.well-known/openid-configuration",
+		"ssa",
+		"aud",
+		"kid",
+		"ssaId",
+		[]string{"/redirect"},
+		nil,
+		string(signingKeyPEM),
+		string(privateKeyPEM),
+		string(certPEM),
+		"",
+		[]string{},
+		true,
+		false,
+		false,
+		false,
+		http.NetworkConfig{},
+		nil,
+		nil,
+		0,
+		"3.2",
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, jwt.SigningMethodES256, config.TokenSigningMethod)
+	assert.IsType(t, &ecdsa.PrivateKey{}, config.PrivateKey)
+}
+
+func TestSsaScopes(t *testing.T) {
+	claims := jwt.MapClaims{"software_roles": []string{"AISP", "CBPII", "UNKNOWN"}}
+	ssa, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts", "fundsconfirmations"}, ssaScopes(ssa))
+	assert.Nil(t, ssaScopes("ssa"))
 }
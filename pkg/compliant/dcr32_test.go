@@ -1,12 +1,16 @@
 package compliant
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -16,7 +20,7 @@ func TestNewDCR32(t *testing.T) {
 
 	assert.Equal(t, "1.0", manifest.Version())
 	assert.Equal(t, "DCR32", manifest.Name())
-	assert.Equal(t, 10, len(manifest.Scenarios()))
+	assert.Equal(t, 20, len(manifest.Scenarios()))
 }
 
 func TestDCR32ValidateOIDCConfigRegistrationURL(t *testing.T) {
@@ -30,11 +34,23 @@ func TestDCR32ValidateOIDCConfigRegistrationURL(t *testing.T) {
 	assert.Equal(t, specLinkDiscovery, scenario.Spec())
 }
 
+func TestDCR32ValidateOIDCConfigRegistrationURL_SkipsOverriddenDiscovery(t *testing.T) {
+	scenario := DCR32ValidateOIDCConfigRegistrationURL(DCR32Config{DiscoveryOverridden: true})
+	result := scenario.Run()
+
+	name := "(SKIP discovery document read from file or overridden) Validate OIDC Config Registration URL"
+	assert.Equal(t, name, scenario.Name())
+	assert.False(t, result.Fail())
+}
+
 func TestDCR32CreateSoftwareClient(t *testing.T) {
+	validator, err := schema.NewValidator("3.2")
+	require.NoError(t, err)
 	scenario := DCR32CreateSoftwareClient(
 		DCR32Config{DeleteImplemented: true},
 		&http.Client{},
 		auth.NewAuthoriserBuilder(),
+		validator,
 	)
 
 	assert.Equal(t, "DCR-002", scenario.Id())
@@ -43,6 +59,20 @@ func TestDCR32CreateSoftwareClient(t *testing.T) {
 	assert.Equal(t, specLinkRegisterSoftware, scenario.Spec())
 }
 
+func TestDCR32CreateSoftwareClientTestCases_ValidatesResponseWithValidator(t *testing.T) {
+	validator, err := schema.NewValidator("3.2")
+	require.NoError(t, err)
+
+	authoriserBuilder := auth.NewAuthoriserBuilder()
+	withoutValidator := DCR32CreateSoftwareClientTestCases(DCR32Config{}, &http.Client{}, authoriserBuilder, nil)
+	withValidator := DCR32CreateSoftwareClientTestCases(DCR32Config{}, &http.Client{}, authoriserBuilder, validator)
+
+	require.Len(t, withoutValidator, 2)
+	require.Len(t, withValidator, 2)
+	assert.Len(t, withoutValidator[0].(testCase).steps, 4)
+	assert.Len(t, withValidator[0].(testCase).steps, 7)
+}
+
 func TestDCR32DeleteSoftwareClient(t *testing.T) {
 	scenario := DCR32DeleteSoftwareClient(
 		DCR32Config{DeleteImplemented: true},
@@ -85,6 +115,22 @@ func TestDCR32CreateInvalidRegistrationRequest(t *testing.T) {
 	assert.Equal(t, specLinkRegisterSoftware, scenario.Spec())
 }
 
+func TestDCR32CreateInvalidRegistrationRequest_RS256OnlyWithRSAKey(t *testing.T) {
+	rsaScenario := DCR32CreateInvalidRegistrationRequest(
+		DCR32Config{PrivateKey: &rsa.PrivateKey{}},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+	ecScenario := DCR32CreateInvalidRegistrationRequest(
+		DCR32Config{PrivateKey: &ecdsa.PrivateKey{}},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	assert.Len(t, rsaScenario.(scenario).tcs, 5)
+	assert.Len(t, ecScenario.(scenario).tcs, 4)
+}
+
 func TestDCR32RetrieveSoftwareClient(t *testing.T) {
 	validator, err := schema.NewValidator("3.2")
 	require.NoError(t, err)
@@ -145,10 +191,13 @@ func TestDCR32RetrieveWithInvalidCredentials(t *testing.T) {
 }
 
 func TestDCR32UpdateSoftwareClient(t *testing.T) {
+	validator, err := schema.NewValidator("3.2")
+	require.NoError(t, err)
 	scenario := DCR32UpdateSoftwareClient(
 		DCR32Config{PutImplemented: true},
 		&http.Client{},
 		auth.NewAuthoriserBuilder(),
+		validator,
 	)
 
 	assert.Equal(t, "DCR-008", scenario.Id())
@@ -158,10 +207,13 @@ func TestDCR32UpdateSoftwareClient(t *testing.T) {
 }
 
 func TestDCR32UpdateSoftwareClientDisabled(t *testing.T) {
+	validator, err := schema.NewValidator("3.2")
+	require.NoError(t, err)
 	scenario := DCR32UpdateSoftwareClient(
 		DCR32Config{PutImplemented: false},
 		&http.Client{},
 		auth.NewAuthoriserBuilder(),
+		validator,
 	)
 
 	assert.Equal(t, "DCR-008", scenario.Id())
@@ -207,3 +259,221 @@ func TestDCR32RegisterWrongResponseTypes(t *testing.T) {
 	assert.Equal(t, name, scenario.Name())
 	assert.Equal(t, specLinkRegisterSoftware, scenario.Spec())
 }
+
+func TestDCR32UpdateSoftwareClientPersisted(t *testing.T) {
+	scenario := DCR32UpdateSoftwareClientPersisted(
+		DCR32Config{PutImplemented: true, GetImplemented: true},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	assert.Equal(t, "DCR-012", scenario.Id())
+	name := "When I update a registered software the changes should be returned when retrieving it"
+	assert.Equal(t, name, scenario.Name())
+	assert.Equal(t, specLinkUpdateSoftware, scenario.Spec())
+}
+
+func TestDCR32UpdateSoftwareClientPersisted_GetNotImplemented(t *testing.T) {
+	scenario := DCR32UpdateSoftwareClientPersisted(
+		DCR32Config{PutImplemented: true, GetImplemented: false},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	result := scenario.Run()
+
+	name := "(SKIP PUT or GET endpoint not implemented) " +
+		"When I update a registered software the changes should be returned when retrieving it"
+	assert.Equal(t, name, scenario.Name())
+	assert.False(t, result.Fail())
+}
+
+func TestDCR32ValidateOIDCDiscoveryDocument(t *testing.T) {
+	scenario := DCR32ValidateOIDCDiscoveryDocument(DCR32Config{})
+
+	assert.Equal(t, "DCR-013", scenario.Id())
+	assert.Equal(t, "Validate OIDC discovery document", scenario.Name())
+	assert.Equal(t, specLinkDiscovery, scenario.Spec())
+}
+
+func TestDCR32ValidateOIDCDiscoveryDocument_SkipsOverriddenDiscovery(t *testing.T) {
+	scenario := DCR32ValidateOIDCDiscoveryDocument(DCR32Config{DiscoveryOverridden: true})
+	result := scenario.Run()
+
+	name := "(SKIP discovery document read from file or overridden) Validate OIDC discovery document"
+	assert.Equal(t, name, scenario.Name())
+	assert.False(t, result.Fail())
+}
+
+func TestDCR32ClientSecretSentInWrongPlace(t *testing.T) {
+	scenario := DCR32ClientSecretSentInWrongPlace(
+		DCR32Config{
+			OpenIDConfig: openid.Configuration{
+				TokenEndpointAuthMethodsSupported: []string{"tls_client_auth", "client_secret_post"},
+			},
+		},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	assert.Equal(t, "DCR-014", scenario.Id())
+	name := "When I send the client secret using a different auth method than registered it should be rejected"
+	assert.Equal(t, name, scenario.Name())
+	assert.Equal(t, specLinkRegisterSoftware, scenario.Spec())
+}
+
+func TestDCR32ClientSecretSentInWrongPlace_SkipsWithoutClientSecretAuthMethod(t *testing.T) {
+	scenario := DCR32ClientSecretSentInWrongPlace(
+		DCR32Config{
+			OpenIDConfig: openid.Configuration{
+				TokenEndpointAuthMethodsSupported: []string{"tls_client_auth"},
+			},
+		},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	result := scenario.Run()
+
+	name := "(SKIP client_secret_basic or client_secret_post not supported) " +
+		"When I send the client secret using a different auth method than registered it should be rejected"
+	assert.Equal(t, name, scenario.Name())
+	assert.False(t, result.Fail())
+}
+
+func TestDCR32ClientCredentialsGrantCertificateBound(t *testing.T) {
+	boundScenario := DCR32ClientCredentialsGrantCertificateBound(
+		DCR32Config{GetImplemented: true, SelfSignedClient: &http.Client{}},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	assert.Equal(t, "DCR-015", boundScenario.Id())
+	name := "Client credentials grant access token should be bound to the transport certificate"
+	assert.Equal(t, name, boundScenario.Name())
+	assert.Equal(t, specLinkRegisterSoftware, boundScenario.Spec())
+	tcs := boundScenario.(scenario).tcs
+	assert.Len(t, tcs, 4)
+	assert.Len(t, tcs[2].(testCase).steps, 2)
+}
+
+func TestDCR32ClientCredentialsGrantCertificateBound_GetNotImplemented(t *testing.T) {
+	boundScenario := DCR32ClientCredentialsGrantCertificateBound(
+		DCR32Config{SelfSignedClient: &http.Client{}},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	assert.Len(t, boundScenario.(scenario).tcs[2].(testCase).steps, 1)
+}
+
+func TestDCR32ClientCredentialsGrantResponse(t *testing.T) {
+	responseScenario := DCR32ClientCredentialsGrantResponse(DCR32Config{}, &http.Client{}, auth.NewAuthoriserBuilder())
+
+	assert.Equal(t, "DCR-016", responseScenario.Id())
+	name := "Client credentials grant token response should be a valid Bearer token response"
+	assert.Equal(t, name, responseScenario.Name())
+	assert.Len(t, responseScenario.(scenario).tcs, 4)
+}
+
+func TestDCR32InvalidClientCredentialsGrant(t *testing.T) {
+	authoriserBuilder := auth.NewAuthoriserBuilder()
+
+	tlsScenario := DCR32InvalidClientCredentialsGrant(
+		DCR32Config{},
+		&http.Client{},
+		authoriserBuilder.WithAuthMethod("tls_client_auth"),
+	)
+	jwtScenario := DCR32InvalidClientCredentialsGrant(
+		DCR32Config{},
+		&http.Client{},
+		authoriserBuilder.WithAuthMethod("private_key_jwt"),
+	)
+
+	assert.Equal(t, "DCR-017", tlsScenario.Id())
+	assert.Equal(t, "When I send an invalid client credentials grant request it should be rejected", tlsScenario.Name())
+	assert.Len(t, tlsScenario.(scenario).tcs[2].(testCase).steps, 1)
+	assert.Len(t, jwtScenario.(scenario).tcs[2].(testCase).steps, 5)
+}
+
+func TestDCR32ClientCredentialsGrantUnregisteredScope(t *testing.T) {
+	scopeScenario := DCR32ClientCredentialsGrantUnregisteredScope(
+		DCR32Config{Scopes: []string{"accounts"}},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	assert.Equal(t, "DCR-018", scopeScenario.Id())
+	name := "When I request a scope outside the registration in a client credentials grant it should be rejected"
+	assert.Equal(t, name, scopeScenario.Name())
+	assert.Len(t, scopeScenario.(scenario).tcs, 4)
+}
+
+func TestDCR32ClientCredentialsGrantUnregisteredScope_SkipsWhenAllScopesRegistered(t *testing.T) {
+	scopeScenario := DCR32ClientCredentialsGrantUnregisteredScope(
+		DCR32Config{Scopes: []string{"accounts", "payments", "fundsconfirmations"}},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	result := scopeScenario.Run()
+
+	assert.True(t, strings.HasPrefix(scopeScenario.Name(), "(SKIP no scope outside the registration) "))
+	assert.False(t, result.Fail())
+}
+
+func TestDCR32ValidateRegistrationEndpointTLS(t *testing.T) {
+	tlsScenario := DCR32ValidateRegistrationEndpointTLS(DCR32Config{ServerCertExpiryDays: 30}, &http.Client{})
+
+	assert.Equal(t, "DCR-019", tlsScenario.Id())
+	assert.Equal(t, "Registration endpoint TLS configuration should be FAPI compliant", tlsScenario.Name())
+	assert.Equal(t, specLinkFAPITLS, tlsScenario.Spec())
+	assert.Len(t, tlsScenario.(scenario).tcs, 2)
+	assert.Len(t, tlsScenario.(scenario).tcs[0].(testCase).steps, 3)
+	assert.Len(t, tlsScenario.(scenario).tcs[1].(testCase).steps, 2)
+}
+
+func TestDCR32RegisterWithInvalidTransportCert(t *testing.T) {
+	invalidCertScenario := DCR32RegisterWithInvalidTransportCert(DCR32Config{}, auth.NewAuthoriserBuilder())
+
+	assert.Equal(t, "DCR-020", invalidCertScenario.Id())
+	assert.Equal(t, "Registering without a valid transport certificate should be rejected", invalidCertScenario.Name())
+	tcs := invalidCertScenario.(scenario).tcs
+	require.Len(t, tcs, 3)
+	assert.Equal(t, "Register software client without client certificate", tcs[0].(testCase).name)
+	assert.Equal(t, "Register software client with a certificate from an untrusted CA", tcs[1].(testCase).name)
+	assert.Equal(t, "Register software client with an expired certificate", tcs[2].(testCase).name)
+	assert.Len(t, tcs[0].(testCase).steps, 2)
+}
+
+func TestDCR32ClientCredentialsGrantWithInvalidTransportCert(t *testing.T) {
+	invalidCertScenario := DCR32ClientCredentialsGrantWithInvalidTransportCert(
+		DCR32Config{},
+		&http.Client{},
+		auth.NewAuthoriserBuilder(),
+	)
+
+	assert.Equal(t, "DCR-021", invalidCertScenario.Id())
+	name := "Client credentials grant without a valid transport certificate should be rejected"
+	assert.Equal(t, name, invalidCertScenario.Name())
+	tcs := invalidCertScenario.(scenario).tcs
+	require.Len(t, tcs, 4)
+	assert.Len(t, tcs[2].(testCase).steps, 3)
+}
+
+func TestUnregisteredScope(t *testing.T) {
+	assert.Equal(t, "payments", unregisteredScope(nil))
+	assert.Equal(t, "accounts", unregisteredScope([]string{"payments"}))
+	assert.Equal(t, "", unregisteredScope([]string{"fundsconfirmations", "payments", "accounts"}))
+}
+
+func TestTokenEndpointClient(t *testing.T) {
+	secureClient := &http.Client{}
+	selfSignedClient := &http.Client{}
+	cfg := DCR32Config{SelfSignedClient: selfSignedClient}
+	authoriserBuilder := auth.NewAuthoriserBuilder()
+
+	assert.True(t, secureClient == tokenEndpointClient(cfg, secureClient, authoriserBuilder))
+	selfSignedBuilder := authoriserBuilder.WithAuthMethod("self_signed_tls_client_auth")
+	assert.True(t, selfSignedClient == tokenEndpointClient(cfg, secureClient, selfSignedBuilder))
+}
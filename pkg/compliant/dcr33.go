@@ -7,15 +7,25 @@ func NewDCR33(cfg DCR32Config) (Manifest, error) {
 
 	scenarios := Scenarios{
 		DCR32ValidateOIDCConfigRegistrationURL(cfg),
-		DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder),
+		DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32DeleteSoftwareClient(cfg, secureClient, authoriserBuilder),
 		DCR32CreateInvalidRegistrationRequest(cfg, secureClient, authoriserBuilder),
 		DCR32RetrieveSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32RetrieveWithInvalidCredentials(cfg, secureClient, authoriserBuilder),
-		DCR32UpdateSoftwareClient(cfg, secureClient, authoriserBuilder),
+		DCR32UpdateSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32UpdateSoftwareClientWithWrongId(cfg, secureClient, authoriserBuilder),
 		DCR32RetrieveSoftwareClientWrongId(cfg, secureClient, authoriserBuilder),
 		DCR32RegisterSoftwareWrongResponseType(cfg, secureClient, authoriserBuilder),
+		DCR32UpdateSoftwareClientPersisted(cfg, secureClient, authoriserBuilder),
+		DCR32ValidateOIDCDiscoveryDocument(cfg),
+		DCR32ClientSecretSentInWrongPlace(cfg, secureClient, authoriserBuilder),
+		DCR32ClientCredentialsGrantCertificateBound(cfg, secureClient, authoriserBuilder),
+		DCR32ClientCredentialsGrantResponse(cfg, secureClient, authoriserBuilder),
+		DCR32InvalidClientCredentialsGrant(cfg, secureClient, authoriserBuilder),
+		DCR32ClientCredentialsGrantUnregisteredScope(cfg, secureClient, authoriserBuilder),
+		DCR32ValidateRegistrationEndpointTLS(cfg, secureClient),
+		DCR32RegisterWithInvalidTransportCert(cfg, authoriserBuilder),
+		DCR32ClientCredentialsGrantWithInvalidTransportCert(cfg, secureClient, authoriserBuilder),
 	}
 
 	return NewManifest("DCR33", "1.0", scenarios)
@@ -0,0 +1,58 @@
+package compliant
+
+import (
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuthMethodsManifest(t *testing.T) {
+	cfg := DCR32Config{
+		OpenIDConfig: openid.Configuration{
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "private_key_jwt"},
+		},
+		AuthoriserBuilder: auth.NewAuthoriserBuilder(),
+	}
+	manifest, err := NewDCR32(cfg)
+	require.NoError(t, err)
+
+	manifest, err = NewAuthMethodsManifest(manifest, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DCR32 (all auth methods)", manifest.Name())
+	scenarios := manifest.Scenarios()
+	assert.Len(t, scenarios, 34)
+	ids := make([]string, 0, 14)
+	for _, scenario := range scenarios[20:] {
+		ids = append(ids, scenario.Id())
+	}
+	assert.Equal(t, []string{
+		"DCR-002/private_key_jwt",
+		"DCR-003/private_key_jwt",
+		"DCR-005/private_key_jwt",
+		"DCR-008/private_key_jwt",
+		"DCR-012/private_key_jwt",
+		"DCR-016/private_key_jwt",
+		"DCR-017/private_key_jwt",
+		"DCR-002/client_secret_basic",
+		"DCR-003/client_secret_basic",
+		"DCR-005/client_secret_basic",
+		"DCR-008/client_secret_basic",
+		"DCR-012/client_secret_basic",
+		"DCR-016/client_secret_basic",
+		"DCR-017/client_secret_basic",
+	}, ids)
+}
+
+func TestNewAuthMethodsManifest_FailsWithoutSupportedAuthMethods(t *testing.T) {
+	manifest, err := NewDCR32(DCR32Config{})
+	require.NoError(t, err)
+
+	manifest, err = NewAuthMethodsManifest(manifest, DCR32Config{})
+
+	assert.EqualError(t, err, "no supported token endpoint auth method advertised")
+	assert.Nil(t, manifest)
+}
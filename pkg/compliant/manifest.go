@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
 )
 
 type Manifest interface {
@@ -97,6 +99,16 @@ func NewFilteredManifest(manifest Manifest, expression string) (Manifest, error)
 	)
 }
 
+// NewRecordedManifest records the http traffic of each scenario,
+// the recorder should be the one of the http clients used by the scenarios
+func NewRecordedManifest(manifest Manifest, recorder *http.Recorder) (Manifest, error) {
+	scenarios := make(Scenarios, len(manifest.Scenarios()))
+	for key, scenario := range manifest.Scenarios() {
+		scenarios[key] = NewRecordedScenario(scenario, recorder)
+	}
+	return NewManifest(manifest.Name(), manifest.Version(), scenarios)
+}
+
 func filter(scenarios Scenarios, expression string) Scenarios {
 	var filteredScenarios Scenarios
 	for _, scenario := range scenarios {
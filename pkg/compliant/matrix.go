@@ -0,0 +1,130 @@
+package compliant
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// TargetResult is the manifest result of one target, ie: a brand or environment, of a batch run.
+// Error is set when the manifest couldn't run for the target, ie: the well-known endpoint is unreachable.
+type TargetResult struct {
+	Name   string
+	Config RunConfig
+	Result ManifestResult
+	Error  string
+}
+
+func (r TargetResult) Fail() bool {
+	return r.Error != "" || r.Result.Fail()
+}
+
+type TargetResults []TargetResult
+
+func (r TargetResults) Fail() bool {
+	for _, result := range r {
+		if result.Fail() {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	matrixPass   = "PASS"
+	matrixFail   = "FAIL"
+	matrixError  = "ERROR"
+	matrixNotRun = "-"
+)
+
+// Matrix aggregates the scenario results of a batch run, one row per scenario and one column per target
+type Matrix struct {
+	Targets   []string          `json:"targets"`
+	Scenarios []MatrixScenario  `json:"scenarios"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+type MatrixScenario struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	Results []string `json:"results"`
+}
+
+// NewMatrix builds the scenario by target matrix, scenarios are in the order they first ran.
+// A scenario not run for a target, ie: filtered or from another spec version manifest, is marked `-`.
+func NewMatrix(results TargetResults) Matrix {
+	matrix := Matrix{Targets: make([]string, len(results))}
+	rows := map[string]int{}
+	for key, target := range results {
+		matrix.Targets[key] = target.Name
+		if target.Error != "" {
+			if matrix.Errors == nil {
+				matrix.Errors = map[string]string{}
+			}
+			matrix.Errors[target.Name] = target.Error
+		}
+		for _, scenarioResult := range target.Result.Results {
+			if _, ok := rows[scenarioResult.Id]; !ok {
+				rows[scenarioResult.Id] = len(matrix.Scenarios)
+				matrix.Scenarios = append(matrix.Scenarios, MatrixScenario{
+					Id:      scenarioResult.Id,
+					Name:    scenarioResult.Name,
+					Results: notRunResults(len(results)),
+				})
+			}
+			outcome := matrixPass
+			if scenarioResult.Fail() {
+				outcome = matrixFail
+			}
+			matrix.Scenarios[rows[scenarioResult.Id]].Results[key] = outcome
+		}
+	}
+
+	for key, target := range results {
+		if target.Error == "" {
+			continue
+		}
+		for _, scenario := range matrix.Scenarios {
+			scenario.Results[key] = matrixError
+		}
+	}
+
+	return matrix
+}
+
+func notRunResults(count int) []string {
+	results := make([]string, count)
+	for key := range results {
+		results[key] = matrixNotRun
+	}
+	return results
+}
+
+// Print writes the matrix as a table followed by the targets that couldn't run
+func (m Matrix) Print(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintf(table, "Scenario\t%s\n", strings.Join(m.Targets, "\t"))
+	if err != nil {
+		return err
+	}
+	for _, scenario := range m.Scenarios {
+		_, err = fmt.Fprintf(table, "%s\t%s\n", scenario.Id, strings.Join(scenario.Results, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	if err = table.Flush(); err != nil {
+		return err
+	}
+
+	for _, target := range m.Targets {
+		if reason, ok := m.Errors[target]; ok {
+			_, err = fmt.Fprintf(w, "%s %s: %s\n", matrixError, target, reason)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package compliant
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func matrixScenarioResult(id string, pass bool) ScenarioResult {
+	return ScenarioResult{
+		Id:   id,
+		Name: "scenario " + id,
+		TestCaseResults: TestCaseResults{
+			{Name: "tc", Results: step.Results{{Name: "step", Pass: pass}}},
+		},
+	}
+}
+
+func TestNewMatrix(t *testing.T) {
+	results := TargetResults{
+		{
+			Name: "brand-a",
+			Result: ManifestResult{Results: []ScenarioResult{
+				matrixScenarioResult("DCR-001", true),
+				matrixScenarioResult("DCR-002", false),
+			}},
+		},
+		{
+			Name:   "brand-b",
+			Result: ManifestResult{Results: []ScenarioResult{matrixScenarioResult("DCR-003", true)}},
+		},
+		{
+			Name:  "brand-c",
+			Error: "well-known endpoint unreachable",
+		},
+	}
+
+	matrix := NewMatrix(results)
+
+	assert.Equal(t, []string{"brand-a", "brand-b", "brand-c"}, matrix.Targets)
+	assert.Equal(t, []MatrixScenario{
+		{Id: "DCR-001", Name: "scenario DCR-001", Results: []string{"PASS", "-", "ERROR"}},
+		{Id: "DCR-002", Name: "scenario DCR-002", Results: []string{"FAIL", "-", "ERROR"}},
+		{Id: "DCR-003", Name: "scenario DCR-003", Results: []string{"-", "PASS", "ERROR"}},
+	}, matrix.Scenarios)
+	assert.Equal(t, map[string]string{"brand-c": "well-known endpoint unreachable"}, matrix.Errors)
+	assert.True(t, results.Fail())
+}
+
+func TestMatrix_Print(t *testing.T) {
+	matrix := Matrix{
+		Targets: []string{"brand-a", "brand-b"},
+		Scenarios: []MatrixScenario{
+			{Id: "DCR-001", Results: []string{"PASS", "ERROR"}},
+			{Id: "DCR-002/private_key_jwt", Results: []string{"FAIL", "ERROR"}},
+		},
+		Errors: map[string]string{"brand-b": "unreachable"},
+	}
+	buf := &bytes.Buffer{}
+
+	err := matrix.Print(buf)
+
+	require.NoError(t, err)
+	expected := "Scenario                 brand-a  brand-b\n" +
+		"DCR-001                  PASS     ERROR\n" +
+		"DCR-002/private_key_jwt  FAIL     ERROR\n" +
+		"ERROR brand-b: unreachable\n"
+	assert.Equal(t, expected, buf.String())
+}
@@ -1,6 +1,7 @@
 package openid
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,13 +10,26 @@ import (
 	"github.com/pkg/errors"
 )
 
+// Configuration OpenID Connect Discovery and FAPI provider metadata
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
 type Configuration struct {
-	RegistrationEndpoint              *string   `json:"registration_endpoint"`
-	TokenEndpoint                     string    `json:"token_endpoint"`
-	RequestObjectSignAlgSupported     []string  `json:"request_object_signing_alg_values_supported"`
-	TokenEndpointAuthMethodsSupported []string  `json:"token_endpoint_auth_methods_supported"`
-	TokenEndpointSigningAlgSupported  *[]string `json:"token_endpoint_auth_signing_alg_values_supported"`
-	ResponseTypesSupported            *[]string `json:"response_types_supported"`
+	Issuer                                string            `json:"issuer"`
+	AuthorizationEndpoint                 string            `json:"authorization_endpoint"`
+	RegistrationEndpoint                  *string           `json:"registration_endpoint"`
+	TokenEndpoint                         string            `json:"token_endpoint"`
+	UserinfoEndpoint                      string            `json:"userinfo_endpoint,omitempty"`
+	IntrospectionEndpoint                 string            `json:"introspection_endpoint,omitempty"`
+	JwksURI                               string            `json:"jwks_uri"`
+	ScopesSupported                       []string          `json:"scopes_supported"`
+	GrantTypesSupported                   []string          `json:"grant_types_supported"`
+	SubjectTypesSupported                 []string          `json:"subject_types_supported"`
+	IdTokenSigningAlgSupported            []string          `json:"id_token_signing_alg_values_supported"`
+	RequestObjectSignAlgSupported         []string          `json:"request_object_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported     []string          `json:"token_endpoint_auth_methods_supported"`
+	TokenEndpointSigningAlgSupported      *[]string         `json:"token_endpoint_auth_signing_alg_values_supported"`
+	ResponseTypesSupported                *[]string         `json:"response_types_supported"`
+	TLSClientCertificateBoundAccessTokens *bool             `json:"tls_client_certificate_bound_access_tokens"`
+	MTLSEndpointAliases                   map[string]string `json:"mtls_endpoint_aliases,omitempty"`
 }
 
 func (c Configuration) RegistrationEndpointAsString() string {
@@ -45,6 +59,43 @@ func Get(url string, client *http.Client) (Configuration, error) {
 	return config, nil
 }
 
+// Load reads the discovery document from a local file, for ASPSPs with an incomplete or wrong well-known document
+func Load(path string) (Configuration, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Configuration{}, errors.Wrap(err, "reading OpenIDConfiguration file")
+	}
+
+	config := Configuration{}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return Configuration{}, errors.Wrap(err, "invalid OpenIDConfiguration file content")
+	}
+
+	return config, nil
+}
+
+// Override replaces the configuration fields named by their discovery metadata property, ex: `token_endpoint`.
+// Properties that aren't part of the configuration are an error, so a misspelt override isn't silently ignored.
+func Override(config Configuration, overrides map[string]interface{}) (Configuration, error) {
+	if len(overrides) == 0 {
+		return config, nil
+	}
+
+	rawOverrides, err := json.Marshal(overrides)
+	if err != nil {
+		return Configuration{}, errors.Wrap(err, "overriding OpenIDConfiguration")
+	}
+
+	// decoding over the configuration only replaces the overridden fields
+	decoder := json.NewDecoder(bytes.NewReader(rawOverrides))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&config); err != nil {
+		return Configuration{}, errors.Wrap(err, "overriding OpenIDConfiguration")
+	}
+
+	return config, nil
+}
+
 func errorFromResponse(response *http.Response, url string) error {
 	responseBody, err := ioutil.ReadAll(response.Body)
 	if err != nil {
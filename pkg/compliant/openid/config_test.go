@@ -1,8 +1,10 @@
 package openid
 
 import (
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +30,7 @@ func TestGetConfig(t *testing.T) {
 	assert.NoError(t, err)
 	registrationEndpoint := "http://registration_endpoint"
 	expected := Configuration{
+		Issuer:                            "issuer",
 		RegistrationEndpoint:              &registrationEndpoint,
 		TokenEndpoint:                     "http://token_endpoint",
 		RequestObjectSignAlgSupported:     []string{"alg1"},
@@ -91,3 +94,87 @@ func TestRegistrationEndpointAsString_EmptyOnNil(t *testing.T) {
 
 	assert.Equal(t, "", c.RegistrationEndpointAsString())
 }
+
+func TestGetConfig_DecodesDiscoveryMetadata(t *testing.T) {
+	body := `{
+		"issuer": "https://as.example.com",
+		"authorization_endpoint": "https://as.example.com/auth",
+		"jwks_uri": "https://as.example.com/jwks",
+		"scopes_supported": ["openid", "accounts"],
+		"id_token_signing_alg_values_supported": ["PS256"],
+		"tls_client_certificate_bound_access_tokens": true,
+		"mtls_endpoint_aliases": {"token_endpoint": "https://mtls.as.example.com/token"}
+		}`
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, err := rw.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	config, err := Get(server.URL, server.Client())
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://as.example.com", config.Issuer)
+	assert.Equal(t, "https://as.example.com/auth", config.AuthorizationEndpoint)
+	assert.Equal(t, "https://as.example.com/jwks", config.JwksURI)
+	assert.Equal(t, []string{"openid", "accounts"}, config.ScopesSupported)
+	assert.Equal(t, []string{"PS256"}, config.IdTokenSigningAlgSupported)
+	require.NotNil(t, config.TLSClientCertificateBoundAccessTokens)
+	assert.True(t, *config.TLSClientCertificateBoundAccessTokens)
+	assert.Equal(t, map[string]string{"token_endpoint": "https://mtls.as.example.com/token"}, config.MTLSEndpointAliases)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openid-configuration.json")
+	body := `{"issuer": "https://as.example.com", "token_endpoint": "https://as.example.com/token"}`
+	require.NoError(t, ioutil.WriteFile(path, []byte(body), 0600))
+
+	config, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, Configuration{Issuer: "https://as.example.com", TokenEndpoint: "https://as.example.com/token"}, config)
+}
+
+func TestLoad_HandlesInvalidContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openid-configuration.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`NOT JSON`), 0600))
+
+	_, err := Load(path)
+
+	assert.EqualError(
+		t,
+		err,
+		"invalid OpenIDConfiguration file content: invalid character 'N' looking for beginning of value",
+	)
+}
+
+func TestOverride(t *testing.T) {
+	registrationEndpoint := "https://as.example.com/register"
+	config := Configuration{
+		Issuer:                            "https://as.example.com",
+		TokenEndpoint:                     "https://as.example.com/token",
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic"},
+	}
+	overrides := map[string]interface{}{
+		"registration_endpoint":                       registrationEndpoint,
+		"token_endpoint_auth_methods_supported":       []interface{}{"private_key_jwt", "tls_client_auth"},
+		"request_object_signing_alg_values_supported": []interface{}{"PS256"},
+	}
+
+	overridden, err := Override(config, overrides)
+
+	require.NoError(t, err)
+	assert.Equal(t, Configuration{
+		Issuer:                            "https://as.example.com",
+		RegistrationEndpoint:              &registrationEndpoint,
+		TokenEndpoint:                     "https://as.example.com/token",
+		RequestObjectSignAlgSupported:     []string{"PS256"},
+		TokenEndpointAuthMethodsSupported: []string{"private_key_jwt", "tls_client_auth"},
+	}, overridden)
+}
+
+func TestOverride_FailsOnUnknownProperty(t *testing.T) {
+	_, err := Override(Configuration{}, map[string]interface{}{"token_endpont": "https://as.example.com/token"})
+
+	assert.EqualError(t, err, "overriding OpenIDConfiguration: json: unknown field \"token_endpont\"")
+}
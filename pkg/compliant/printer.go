@@ -28,7 +28,15 @@ type printer struct {
 }
 
 func (p printer) Print(result ManifestResult) error {
+	authMethod := ""
 	for _, scenarioResult := range result.Results {
+		if scenarioResult.AuthMethod != authMethod {
+			authMethod = scenarioResult.AuthMethod
+			_, err := fmt.Fprintf(p.output, "=== Token endpoint auth method: %s\n", authMethod)
+			if err != nil {
+				return err
+			}
+		}
 		_, err := fmt.Fprintf(p.output, "=== Scenario: %s - %s\n", scenarioResult.Id, scenarioResult.Name)
 		if err != nil {
 			return err
@@ -65,6 +73,19 @@ func (p printer) printColourTestResult(result step.Result) error {
 		if err != nil {
 			return err
 		}
+		// interaction ids of failed steps are what ASPSP support teams ask for
+		for _, interactionID := range result.Debug.InteractionIDs {
+			_, err = fmt.Fprintf(p.output, "\t\t\tx-fapi-interaction-id: %s\n", interactionID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, warning := range result.Warnings {
+		_, err := fmt.Fprintf(p.output, "\t\t\t%s %s\n", aurora.Yellow("WARN"), warning)
+		if err != nil {
+			return err
+		}
 	}
 	if p.debug {
 		return p.printColourDebugMessages(result.Debug)
@@ -64,3 +64,60 @@ func TestNewPrinter(t *testing.T) {
 
 	assert.Equal(t, g, w.Bytes())
 }
+
+func TestPrinter_GroupsByAuthMethod(t *testing.T) {
+	result := ManifestResult{
+		Results: []ScenarioResult{
+			{Id: "1", Name: "scenario one"},
+			{Id: "2/private_key_jwt", Name: "scenario two", AuthMethod: "private_key_jwt"},
+			{Id: "3/private_key_jwt", Name: "scenario three", AuthMethod: "private_key_jwt"},
+		},
+	}
+	w := &bytes.Buffer{}
+	printer := NewPrinterWithOptions(false, w)
+
+	err := printer.Print(result)
+	require.NoError(t, err)
+
+	expected := "=== Scenario: 1 - scenario one\n" +
+		"=== Token endpoint auth method: private_key_jwt\n" +
+		"=== Scenario: 2/private_key_jwt - scenario two\n" +
+		"=== Scenario: 3/private_key_jwt - scenario three\n"
+	assert.Equal(t, expected, w.String())
+}
+
+func TestPrinter_PrintsInteractionIDsOfFailedSteps(t *testing.T) {
+	result := ManifestResult{
+		Results: []ScenarioResult{
+			{
+				Id:   "1",
+				Name: "scenario one",
+				TestCaseResults: TestCaseResults{
+					{
+						Name: "tc one",
+						Results: []step.Result{
+							{
+								Name:  "step one",
+								Pass:  true,
+								Debug: step.DebugMessages{InteractionIDs: []string{"passed-id"}},
+							},
+							{
+								Name:       "step two",
+								FailReason: "reasons",
+								Debug:      step.DebugMessages{InteractionIDs: []string{"failed-id"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	w := &bytes.Buffer{}
+	printer := NewPrinterWithOptions(false, w)
+
+	err := printer.Print(result)
+	require.NoError(t, err)
+
+	assert.NotContains(t, w.String(), "passed-id")
+	assert.Contains(t, w.String(), "\t\t\tx-fapi-interaction-id: failed-id\n")
+}
@@ -0,0 +1,60 @@
+package compliant
+
+import (
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/redact"
+)
+
+// NewRedactedListener masks secrets, ie: authorization headers, client secrets, access tokens and
+// JWT signatures, in the results passed to a listener, so printed debug and reports can be shared
+func NewRedactedListener(listener ListenerFunc) ListenerFunc {
+	return func(result ManifestResult) error {
+		return listener(result.Redacted())
+	}
+}
+
+// Redacted is a copy of the manifest result with secrets masked in step results and recorded traffic
+func (r ManifestResult) Redacted() ManifestResult {
+	redacted := r
+	redacted.Results = make([]ScenarioResult, len(r.Results))
+	for key, result := range r.Results {
+		redacted.Results[key] = result.Redacted()
+	}
+	return redacted
+}
+
+func (r ScenarioResult) Redacted() ScenarioResult {
+	redacted := r
+	redacted.TestCaseResults = make(TestCaseResults, len(r.TestCaseResults))
+	for key, tcResult := range r.TestCaseResults {
+		redacted.TestCaseResults[key] = tcResult.Redacted()
+	}
+	if r.Traffic != nil {
+		redacted.Traffic = make([]http.HAREntry, len(r.Traffic))
+		for key, entry := range r.Traffic {
+			redacted.Traffic[key] = entry.Redacted()
+		}
+	}
+	return redacted
+}
+
+func (r TestCaseResult) Redacted() TestCaseResult {
+	redacted := r
+	redacted.Results = make(step.Results, len(r.Results))
+	for key, result := range r.Results {
+		redacted.Results[key] = result.Redacted()
+	}
+	return redacted
+}
+
+// Redacted is a copy of the batch results with secrets masked, including in the errors of targets that didn't run
+func (r TargetResults) Redacted() TargetResults {
+	redacted := make(TargetResults, len(r))
+	for key, target := range r {
+		target.Result = target.Result.Redacted()
+		target.Error = redact.String(target.Error)
+		redacted[key] = target
+	}
+	return redacted
+}
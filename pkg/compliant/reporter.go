@@ -7,18 +7,23 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
 )
 
 type RunConfig struct {
-	WellknownEndpoint string
-	GetImplemented    bool
-	PutImplemented    bool
-	DeleteImplemented bool
-	Environment       string
-	Brand             string
+	WellknownEndpoint  string
+	GetImplemented     bool
+	PutImplemented     bool
+	DeleteImplemented  bool
+	Environment        string
+	Brand              string
+	DiscoveryDocument  string   `json:",omitempty"`
+	DiscoveryOverrides []string `json:",omitempty"`
 }
 
 func NewReporter(config RunConfig, debug bool, doneSignal chan<- bool, serverAddr string) reporter {
@@ -39,10 +44,27 @@ type reporter struct {
 
 // Report marshals the result and debug into json, zips them, then starts a server to host the generated zip file.
 func (r reporter) Report(result ManifestResult) error {
-	reportJson, err := json.MarshalIndent(r.mapToReport(result), "", " ")
+	files, err := r.files(result)
+	if err != nil {
+		return err
+	}
+
+	b, err := ZipReportFiles(files)
 	if err != nil {
 		return err
 	}
+
+	r.startServer(b)
+
+	return nil
+}
+
+// files returns the report, debug log when enabled and run config of a manifest result
+func (r reporter) files(result ManifestResult) ([]ReportFile, error) {
+	reportJson, err := json.MarshalIndent(r.mapToReport(result), "", " ")
+	if err != nil {
+		return nil, err
+	}
 	var files = []ReportFile{
 		{"report.json", string(reportJson)},
 	}
@@ -51,7 +73,7 @@ func (r reporter) Report(result ManifestResult) error {
 		var debugJson []byte
 		debugJson, err = json.MarshalIndent(r.GetDebugLog(result), "", " ")
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		files = append(files, ReportFile{"debug.json", string(debugJson)})
@@ -59,18 +81,28 @@ func (r reporter) Report(result ManifestResult) error {
 
 	config, err := json.MarshalIndent(r.config, "", " ")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	files = append(files, ReportFile{"config.json", string(config)})
 
-	b, err := ZipReportFiles(files)
-	if err != nil {
-		return err
+	for _, scenario := range result.Results {
+		if len(scenario.Traffic) == 0 {
+			continue
+		}
+		var har []byte
+		har, err = json.MarshalIndent(http2.NewHAR(scenario.Traffic), "", " ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, ReportFile{harFileName(scenario.Id), string(har)})
 	}
 
-	r.startServer(b)
+	return files, nil
+}
 
-	return nil
+// harFileName is the HAR file of a scenario, auth method scenario ids like `DCR-002/private_key_jwt` are flattened
+func harFileName(scenarioId string) string {
+	return path.Join("har", strings.ReplaceAll(scenarioId, "/", "_")+".har")
 }
 
 func (r reporter) startServer(report io.Reader) {
@@ -86,6 +118,51 @@ func (r reporter) startServer(report io.Reader) {
 	}()
 }
 
+func NewBatchReporter(debug bool, doneSignal chan<- bool, serverAddr string) batchReporter {
+	return batchReporter{
+		debug:          debug,
+		doneSignalChan: doneSignal,
+		serverAddr:     serverAddr,
+	}
+}
+
+type batchReporter struct {
+	debug          bool
+	doneSignalChan chan<- bool
+	serverAddr     string
+}
+
+// Report zips a report per target, in a directory named after the target, and the scenario by target matrix,
+// then starts a server to host the generated zip file.
+func (r batchReporter) Report(results TargetResults) error {
+	var files []ReportFile
+	for _, target := range results {
+		targetReporter := NewReporter(target.Config, r.debug, r.doneSignalChan, r.serverAddr)
+		targetFiles, err := targetReporter.files(target.Result)
+		if err != nil {
+			return err
+		}
+		for _, file := range targetFiles {
+			files = append(files, ReportFile{path.Join(target.Name, file.Name), file.Body})
+		}
+	}
+
+	matrixJson, err := json.MarshalIndent(NewMatrix(results), "", " ")
+	if err != nil {
+		return err
+	}
+	files = append(files, ReportFile{"matrix.json", string(matrixJson)})
+
+	b, err := ZipReportFiles(files)
+	if err != nil {
+		return err
+	}
+
+	NewReporter(RunConfig{}, r.debug, r.doneSignalChan, r.serverAddr).startServer(b)
+
+	return nil
+}
+
 type DebugLine struct {
 	Time     string         `json:"time,omitempty"`
 	Message  string         `json:"message,omitempty"`
@@ -106,10 +183,11 @@ func (r reporter) GetDebugLog(result ManifestResult) []DebugLine {
 						Message: message.Message,
 						Time:    message.Time.Format(time.RFC3339),
 						Scenario: ReportScenario{
-							Id:   scenario.Id,
-							Name: scenario.Name,
-							Spec: scenario.Spec,
-							Pass: !scenario.Fail(),
+							Id:         scenario.Id,
+							Name:       scenario.Name,
+							Spec:       scenario.Spec,
+							AuthMethod: scenario.AuthMethod,
+							Pass:       !scenario.Fail(),
 						},
 						Testcase: ReportTestcase{
 							Name: testcase.Name,
@@ -132,18 +210,21 @@ func (r reporter) mapToReport(result ManifestResult) Report {
 	results := make([]ReportScenario, len(result.Results))
 	for key, scenario := range result.Results {
 		results[key] = ReportScenario{
-			Id:        scenario.Id,
-			Name:      scenario.Name,
-			Spec:      scenario.Spec,
-			Pass:      !scenario.Fail(),
-			TestCases: r.mapTCSToReport(scenario.TestCaseResults),
+			Id:         scenario.Id,
+			Name:       scenario.Name,
+			Spec:       scenario.Spec,
+			AuthMethod: scenario.AuthMethod,
+			Pass:       !scenario.Fail(),
+			TestCases:  r.mapTCSToReport(scenario.TestCaseResults),
 		}
 	}
 	return Report{
-		Name:      result.Name,
-		Version:   result.Version,
-		Pass:      !result.Fail(),
-		Scenarios: results,
+		Name:               result.Name,
+		Version:            result.Version,
+		Pass:               !result.Fail(),
+		DiscoveryDocument:  r.config.DiscoveryDocument,
+		DiscoveryOverrides: r.config.DiscoveryOverrides,
+		Scenarios:          results,
 	}
 }
 
@@ -163,27 +244,34 @@ func (r reporter) mapStepsToReport(results step.Results) []ReportStep {
 	stepResults := make([]ReportStep, len(results))
 	for key, result := range results {
 		stepResults[key] = ReportStep{
-			Name:   result.Name,
-			Pass:   result.Pass,
-			Reason: result.FailReason,
+			Name:           result.Name,
+			Pass:           result.Pass,
+			Reason:         result.FailReason,
+			Warnings:       result.Warnings,
+			InteractionIDs: result.Debug.InteractionIDs,
 		}
 	}
 	return stepResults
 }
 
+// Report flags a run against a local discovery document or overridden discovery metadata,
+// as its results don't reflect the ASPSP well-known document
 type Report struct {
-	Name      string           `json:"name"`
-	Version   string           `json:"version"`
-	Pass      bool             `json:"pass"`
-	Scenarios []ReportScenario `json:"scenarios,omitempty"`
+	Name               string           `json:"name"`
+	Version            string           `json:"version"`
+	Pass               bool             `json:"pass"`
+	DiscoveryDocument  string           `json:"discovery_document,omitempty"`
+	DiscoveryOverrides []string         `json:"discovery_overrides,omitempty"`
+	Scenarios          []ReportScenario `json:"scenarios,omitempty"`
 }
 
 type ReportScenario struct {
-	Id        string           `json:"id"`
-	Name      string           `json:"name"`
-	Spec      string           `json:"spec"`
-	Pass      bool             `json:"pass"`
-	TestCases []ReportTestcase `json:"test_cases,omitempty"`
+	Id         string           `json:"id"`
+	Name       string           `json:"name"`
+	Spec       string           `json:"spec"`
+	AuthMethod string           `json:"auth_method,omitempty"`
+	Pass       bool             `json:"pass"`
+	TestCases  []ReportTestcase `json:"test_cases,omitempty"`
 }
 
 type ReportTestcase struct {
@@ -193,10 +281,12 @@ type ReportTestcase struct {
 }
 
 type ReportStep struct {
-	Name   string   `json:"name"`
-	Pass   bool     `json:"pass"`
-	Reason string   `json:"reason,omitempty"`
-	Debug  []string `json:"debug,omitempty"`
+	Name           string   `json:"name"`
+	Pass           bool     `json:"pass"`
+	Reason         string   `json:"reason,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+	InteractionIDs []string `json:"interaction_ids,omitempty"`
+	Debug          []string `json:"debug,omitempty"`
 }
 
 type downloadHandler struct {
@@ -2,6 +2,7 @@ package compliant
 
 import (
 	"archive/zip"
+	"bytes"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -123,6 +125,81 @@ func TestNewReporter(t *testing.T) {
 	assert.True(t, configPresent)
 }
 
+func TestReporter_FlagsDiscoveryOverrides(t *testing.T) {
+	config := RunConfig{
+		DiscoveryDocument:  "openid-configuration.json",
+		DiscoveryOverrides: []string{"token_endpoint"},
+	}
+	reporter := NewReporter(config, false, nil, "")
+
+	report := reporter.mapToReport(ManifestResult{Name: "manifest"})
+
+	assert.Equal(t, "openid-configuration.json", report.DiscoveryDocument)
+	assert.Equal(t, []string{"token_endpoint"}, report.DiscoveryOverrides)
+}
+
+func TestReporter_ReportsInteractionIDs(t *testing.T) {
+	results := step.Results{
+		{Name: "step one", Pass: true, Debug: step.DebugMessages{InteractionIDs: []string{"interaction-id"}}},
+	}
+
+	steps := NewReporter(RunConfig{}, false, nil, "").mapStepsToReport(results)
+
+	assert.Equal(t, []string{"interaction-id"}, steps[0].InteractionIDs)
+}
+
+func TestReporter_AddsScenarioHARFiles(t *testing.T) {
+	result := ManifestResult{
+		Results: []ScenarioResult{
+			{Id: "DCR-001"},
+			{Id: "DCR-002/private_key_jwt", Traffic: []http2.HAREntry{{StartedDateTime: "now"}}},
+		},
+	}
+
+	files, err := NewReporter(RunConfig{}, false, nil, "").files(result)
+
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.Equal(t, "har/DCR-002_private_key_jwt.har", files[2].Name)
+	assert.Contains(t, files[2].Body, `"version": "1.2"`)
+}
+
+func TestNewBatchReporter(t *testing.T) {
+	results := TargetResults{
+		{Name: "brand-a", Config: RunConfig{Brand: "Brand A"}},
+		{Name: "brand-b", Config: RunConfig{Brand: "Brand B"}},
+	}
+	doneSignal := make(chan bool, 1)
+	serverAddr := "localhost:8002"
+
+	err := NewBatchReporter(false, doneSignal, serverAddr).Report(results)
+	require.NoError(t, err)
+
+	// wait for http server to start
+	time.Sleep(time.Millisecond * 100)
+
+	r, err := http.Get("http://" + serverAddr + "?download=report")
+	require.NoError(t, err)
+	defer r.Body.Close()
+	b, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	<-doneSignal
+
+	zipReader, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zipReader.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{
+		"brand-a/report.json",
+		"brand-a/config.json",
+		"brand-b/report.json",
+		"brand-b/config.json",
+		"matrix.json",
+	}, names)
+}
+
 func Copy(src, dst string) error {
 	in, err := os.Open(src)
 	if err != nil {
@@ -1,6 +1,11 @@
 package compliant
 
-import "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+import (
+	"fmt"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
 
 type Scenario interface {
 	Run() ScenarioResult
@@ -12,10 +17,13 @@ type Scenario interface {
 type Scenarios []Scenario
 
 type ScenarioResult struct {
-	Id   string
-	Name string
-	Spec string
+	Id         string
+	Name       string
+	Spec       string
+	AuthMethod string
 	TestCaseResults
+	// Traffic is the http traffic of the scenario when recorded
+	Traffic []http.HAREntry
 }
 
 type ScenariosResult []ScenarioResult
@@ -72,3 +80,49 @@ func (s scenario) Run() ScenarioResult {
 		TestCaseResults: results,
 	}
 }
+
+// authMethodScenario runs a scenario for a specific token endpoint auth method,
+// ids are suffixed with the auth method to keep them unique in a manifest
+type authMethodScenario struct {
+	Scenario
+	authMethod string
+}
+
+func NewAuthMethodScenario(scenario Scenario, authMethod string) Scenario {
+	return authMethodScenario{
+		Scenario:   scenario,
+		authMethod: authMethod,
+	}
+}
+
+func (s authMethodScenario) Id() string {
+	return fmt.Sprintf("%s/%s", s.Scenario.Id(), s.authMethod)
+}
+
+func (s authMethodScenario) Run() ScenarioResult {
+	result := s.Scenario.Run()
+	result.Id = s.Id()
+	result.AuthMethod = s.authMethod
+	return result
+}
+
+type recordedScenario struct {
+	Scenario
+	recorder *http.Recorder
+}
+
+// NewRecordedScenario attaches to the scenario result the traffic recorded while the scenario ran
+func NewRecordedScenario(scenario Scenario, recorder *http.Recorder) Scenario {
+	return recordedScenario{
+		Scenario: scenario,
+		recorder: recorder,
+	}
+}
+
+func (s recordedScenario) Run() ScenarioResult {
+	// discard traffic recorded outside of a scenario
+	s.recorder.Take()
+	result := s.Scenario.Run()
+	result.Traffic = s.recorder.Take()
+	return result
+}
@@ -1,9 +1,14 @@
 package compliant
 
 import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScenarioResult(t *testing.T) {
@@ -76,3 +81,39 @@ func TestNewScenario_RunsAllTestCases(t *testing.T) {
 	assert.Equal(t, "some scenario", results.Name)
 	assert.Len(t, results.TestCaseResults, 2)
 }
+
+func TestAuthMethodScenario(t *testing.T) {
+	scenario := NewAuthMethodScenario(
+		NewScenario("DCR-002", "scenario name", "spec link", []TestCase{}),
+		"private_key_jwt",
+	)
+
+	result := scenario.Run()
+
+	assert.Equal(t, "DCR-002/private_key_jwt", scenario.Id())
+	assert.Equal(t, "scenario name", scenario.Name())
+	assert.Equal(t, "DCR-002/private_key_jwt", result.Id)
+	assert.Equal(t, "private_key_jwt", result.AuthMethod)
+}
+
+func TestNewRecordedScenario_AttachesScenarioTraffic(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(rw nethttp.ResponseWriter, r *nethttp.Request) {}))
+	defer server.Close()
+	recorder := http.NewRecorder()
+	client := &nethttp.Client{Transport: recorder.Transport(&nethttp.Transport{})}
+	response, err := client.Get(server.URL + "/outside")
+	require.NoError(t, err)
+	response.Body.Close()
+	recordedScenario := NewRecordedScenario(
+		NewScenario("1", "scenario", "spec", []TestCase{
+			NewTestCase("get", []step.Step{step.NewGetRequest(server.URL+"/inside", "response", client)}),
+		}),
+		recorder,
+	)
+
+	result := recordedScenario.Run()
+
+	require.Len(t, result.Traffic, 1)
+	assert.Equal(t, server.URL+"/inside", result.Traffic[0].Request.URL)
+	assert.Equal(t, "1", result.Id)
+}
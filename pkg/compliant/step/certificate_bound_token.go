@@ -0,0 +1,240 @@
+package step
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+type certificateBoundToken struct {
+	stepName              string
+	grantTokenCtxKey      string
+	clientCtxKey          string
+	introspectionEndpoint string
+	cert                  *x509.Certificate
+	client                *http.Client
+}
+
+// NewCertificateBoundToken checks the client credentials grant access token is bound to the mTLS
+// certificate (RFC8705), reading the `cnf` confirmation claim from JWT access tokens
+// or from the introspection endpoint for opaque tokens.
+// When the token is opaque and introspection is not advertised the binding can't be verified, which is a warning.
+func NewCertificateBoundToken(
+	grantTokenCtxKey, clientCtxKey, introspectionEndpoint string,
+	cert *x509.Certificate,
+	httpClient *http.Client,
+) Step {
+	return certificateBoundToken{
+		stepName:              "Validate access token is certificate bound",
+		grantTokenCtxKey:      grantTokenCtxKey,
+		clientCtxKey:          clientCtxKey,
+		introspectionEndpoint: introspectionEndpoint,
+		cert:                  cert,
+		client:                httpClient,
+	}
+}
+
+type confirmation struct {
+	Cnf struct {
+		X5tS256 string `json:"x5t#S256"`
+	} `json:"cnf"`
+}
+
+func (s certificateBoundToken) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	if s.cert == nil {
+		return NewFailResultWithDebug(s.stepName, "transport certificate not available", debug)
+	}
+
+	debug.Logf("get grant token from ctx var: %s", s.grantTokenCtxKey)
+	grantToken, err := ctx.GetGrantToken(s.grantTokenCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("unable to find grant token %s in context: %v", s.grantTokenCtxKey, err)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	var claims confirmation
+	parts := strings.Split(grantToken.AccessToken, ".")
+	switch {
+	case len(parts) == 3:
+		debug.Log("access token is a jwt, reading cnf claim")
+		claims, err = jwtConfirmation(parts[1])
+	case s.introspectionEndpoint != "":
+		debug.Logf("access token is opaque, introspecting at %s", s.introspectionEndpoint)
+		claims, err = s.introspect(ctx, grantToken.AccessToken, debug)
+	default:
+		warning := "access token is opaque and introspection_endpoint is not advertised, binding not verified"
+		return NewPassResultWithWarnings(s.stepName, []string{warning}, debug)
+	}
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, err.Error(), debug)
+	}
+
+	thumbprint := sha256.Sum256(s.cert.Raw)
+	expected := base64.RawURLEncoding.EncodeToString(thumbprint[:])
+	if claims.Cnf.X5tS256 == "" {
+		return NewFailResultWithDebug(s.stepName, "access token has no cnf x5t#S256 confirmation", debug)
+	}
+	if claims.Cnf.X5tS256 != expected {
+		msg := fmt.Sprintf(
+			"access token cnf x5t#S256 %s does not match transport certificate %s",
+			claims.Cnf.X5tS256,
+			expected,
+		)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	return NewPassResultWithDebug(s.stepName, debug)
+}
+
+func jwtConfirmation(payload string) (confirmation, error) {
+	decoded, err := jwt.DecodeSegment(payload)
+	if err != nil {
+		return confirmation{}, errors.Wrap(err, "decoding access token")
+	}
+	var claims confirmation
+	if err = json.Unmarshal(decoded, &claims); err != nil {
+		return confirmation{}, errors.Wrap(err, "decoding access token")
+	}
+	return claims, nil
+}
+
+func (s certificateBoundToken) introspect(ctx Context, token string, debug *DebugMessages) (confirmation, error) {
+	client, err := ctx.GetClient(s.clientCtxKey)
+	if err != nil {
+		return confirmation{}, errors.Wrapf(err, "unable to find client %s in context", s.clientCtxKey)
+	}
+
+	data := url.Values{}
+	data.Set("token", token)
+	data.Set("client_id", client.Id())
+	req, err := http.NewRequest(http.MethodPost, s.introspectionEndpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return confirmation{}, errors.Wrap(err, "making introspection request")
+	}
+	req.Header.Set("Content-type", "application/x-www-form-urlencoded")
+
+	debug.Log(http2.DebugRequest(req))
+	res, err := s.client.Do(req)
+	if err != nil {
+		return confirmation{}, errors.Wrap(err, "calling introspection endpoint")
+	}
+	defer res.Body.Close()
+	debug.LogInteractionID(res)
+	debug.Log(http2.DebugResponse(res))
+
+	if res.StatusCode != http.StatusOK {
+		return confirmation{}, fmt.Errorf(
+			"unexpected introspection status code %d, should be %d",
+			res.StatusCode,
+			http.StatusOK,
+		)
+	}
+
+	var claims confirmation
+	if err = json.NewDecoder(res.Body).Decode(&claims); err != nil {
+		return confirmation{}, errors.Wrap(err, "decoding introspection response")
+	}
+	return claims, nil
+}
+
+type boundTokenRejected struct {
+	stepName                 string
+	registrationEndpoint     string
+	clientCtxKey             string
+	grantTokenCtxKey         string
+	registrationAccessCtxKey string
+	client                   *http.Client
+}
+
+// NewBoundTokenRejected asserts the client credentials grant access token can't be used to retrieve
+// the client over a connection using a different client certificate than the one the token is bound to.
+// The client is retrieved from the RFC7592 registration_client_uri when the ASPSP returned one.
+// A connection refused at TLS level is inconclusive, the ASPSP didn't trust the other certificate
+// so the token binding wasn't exercised, any other connection error is a failure.
+func NewBoundTokenRejected(
+	registrationEndpoint, clientCtxKey, grantTokenCtxKey, registrationAccessCtxKey string,
+	otherCertHttpClient *http.Client,
+) Step {
+	return boundTokenRejected{
+		stepName:                 "Access token rejected with a different client certificate",
+		registrationEndpoint:     registrationEndpoint,
+		clientCtxKey:             clientCtxKey,
+		grantTokenCtxKey:         grantTokenCtxKey,
+		registrationAccessCtxKey: registrationAccessCtxKey,
+		client:                   otherCertHttpClient,
+	}
+}
+
+func (s boundTokenRejected) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	client, err := ctx.GetClient(s.clientCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("unable to find client %s in context: %v", s.clientCtxKey, err)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	grantToken, err := ctx.GetGrantToken(s.grantTokenCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("unable to find grant token %s in context: %v", s.grantTokenCtxKey, err)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	endpoint, _, err := clientConfiguration(
+		ctx,
+		s.registrationEndpoint,
+		client.Id(),
+		s.grantTokenCtxKey,
+		s.registrationAccessCtxKey,
+	)
+	if err != nil {
+		msg := fmt.Sprintf("unable to resolve client configuration endpoint: %v", err)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		msg := fmt.Sprintf("unable to make request: %s", err.Error())
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+	req.Header.Set("Authorization", "Bearer "+grantToken.AccessToken)
+
+	debug.Log(http2.DebugRequest(req))
+	res, err := s.client.Do(req)
+	if err != nil {
+		if tlsAlert(err) {
+			debug.Logf("connection with a different client certificate refused: %v", err)
+			warning := "inconclusive, the connection with a different client certificate was refused at TLS level " +
+				"so the access token binding wasn't exercised"
+			return NewPassResultWithWarnings(s.stepName, []string{warning}, debug)
+		}
+		msg := fmt.Sprintf("unable to call client configuration endpoint: %v", err)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+	defer res.Body.Close()
+	debug.LogInteractionID(res)
+	debug.Log(http2.DebugResponse(res))
+
+	if res.StatusCode != http.StatusUnauthorized && res.StatusCode != http.StatusForbidden {
+		msg := fmt.Sprintf(
+			"unexpected status code %d, should be %d or %d",
+			res.StatusCode,
+			http.StatusUnauthorized,
+			http.StatusForbidden,
+		)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	return NewPassResultWithDebug(s.stepName, debug)
+}
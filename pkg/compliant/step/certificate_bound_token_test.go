@@ -0,0 +1,168 @@
+package step
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boundCert() (*x509.Certificate, string) {
+	cert := &x509.Certificate{Raw: []byte("transport cert")}
+	thumbprint := sha256.Sum256(cert.Raw)
+	return cert, base64.RawURLEncoding.EncodeToString(thumbprint[:])
+}
+
+func boundTokenCtx(accessToken string) Context {
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewTlsClientAuth(clientID, "http://token"))
+	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{AccessToken: accessToken})
+	return ctx
+}
+
+func TestCertificateBoundToken_JwtAccessToken(t *testing.T) {
+	cert, thumbprint := boundCert()
+	token := unsignedClaims(t, jwt.MapClaims{"cnf": map[string]string{"x5t#S256": thumbprint}})
+	step := NewCertificateBoundToken("grantTokenKey", "clientKey", "", cert, &http.Client{})
+
+	result := step.Run(boundTokenCtx(token))
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Validate access token is certificate bound", result.Name)
+}
+
+func TestCertificateBoundToken_FailsOnDifferentThumbprint(t *testing.T) {
+	cert, thumbprint := boundCert()
+	token := unsignedClaims(t, jwt.MapClaims{"cnf": map[string]string{"x5t#S256": "other"}})
+	step := NewCertificateBoundToken("grantTokenKey", "clientKey", "", cert, &http.Client{})
+
+	result := step.Run(boundTokenCtx(token))
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"access token cnf x5t#S256 other does not match transport certificate "+thumbprint,
+		result.FailReason,
+	)
+}
+
+func TestCertificateBoundToken_FailsOnMissingConfirmation(t *testing.T) {
+	cert, _ := boundCert()
+	token := unsignedClaims(t, jwt.MapClaims{"sub": clientID})
+	step := NewCertificateBoundToken("grantTokenKey", "clientKey", "", cert, &http.Client{})
+
+	result := step.Run(boundTokenCtx(token))
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "access token has no cnf x5t#S256 confirmation", result.FailReason)
+}
+
+func TestCertificateBoundToken_IntrospectsOpaqueToken(t *testing.T) {
+	cert, thumbprint := boundCert()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "opaque", r.PostForm.Get("token"))
+		assert.Equal(t, clientID, r.PostForm.Get("client_id"))
+		_, err := w.Write([]byte(`{"active": true, "cnf": {"x5t#S256": "` + thumbprint + `"}}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	step := NewCertificateBoundToken("grantTokenKey", "clientKey", server.URL, cert, server.Client())
+
+	result := step.Run(boundTokenCtx("opaque"))
+
+	assert.True(t, result.Pass)
+}
+
+func TestCertificateBoundToken_WarnsOnOpaqueTokenWithoutIntrospection(t *testing.T) {
+	cert, _ := boundCert()
+	step := NewCertificateBoundToken("grantTokenKey", "clientKey", "", cert, &http.Client{})
+
+	result := step.Run(boundTokenCtx("opaque"))
+
+	assert.True(t, result.Pass)
+	assert.Equal(
+		t,
+		[]string{"access token is opaque and introspection_endpoint is not advertised, binding not verified"},
+		result.Warnings,
+	)
+}
+
+func TestBoundTokenRejected_PassesOnUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/register/"+clientID, r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	step := NewBoundTokenRejected(server.URL+"/register", "clientKey", "grantTokenKey", "accessKey", server.Client())
+
+	result := step.Run(boundTokenCtx("token"))
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Access token rejected with a different client certificate", result.Name)
+}
+
+func TestBoundTokenRejected_UsesRegistrationClientURI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/clients/"+clientID, r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	ctx := boundTokenCtx("token")
+	ctx.SetRegistrationAccess(
+		"accessKey",
+		auth.RegistrationAccess{ClientURI: server.URL + "/clients/" + clientID, AccessToken: "registration"},
+	)
+	step := NewBoundTokenRejected(server.URL+"/register", "clientKey", "grantTokenKey", "accessKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+}
+
+func TestBoundTokenRejected_InconclusiveOnTLSRejection(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	step := NewBoundTokenRejected(server.URL+"/register", "clientKey", "grantTokenKey", "accessKey", server.Client())
+
+	result := step.Run(boundTokenCtx("token"))
+
+	assert.True(t, result.Pass)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "inconclusive")
+}
+
+func TestBoundTokenRejected_FailsOnNetworkError(t *testing.T) {
+	step := NewBoundTokenRejected("http://127.0.0.1:0/register", "clientKey", "grantTokenKey", "accessKey", &http.Client{})
+
+	result := step.Run(boundTokenCtx("token"))
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "unable to call client configuration endpoint")
+}
+
+func TestBoundTokenRejected_FailsWhenAccepted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	step := NewBoundTokenRejected(server.URL+"/register", "clientKey", "grantTokenKey", "accessKey", server.Client())
+
+	result := step.Run(boundTokenCtx("token"))
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "unexpected status code 200, should be 401 or 403", result.FailReason)
+}
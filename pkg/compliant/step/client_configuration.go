@@ -0,0 +1,47 @@
+package step
+
+import (
+	"fmt"
+)
+
+// clientConfiguration resolves the endpoint and bearer token to retrieve, update or delete a client with.
+// If the ASPSP returned a RFC7592 registration_client_uri and registration_access_token on registration
+// those are used, otherwise the client id is appended to the registration endpoint and the client credentials
+// grant token is used.
+// A grant token explicitly set to invalid takes precedence so invalid credentials scenarios apply to both modes.
+func clientConfiguration(
+	ctx Context,
+	registrationEndpoint, clientId, grantTokenCtxKey, registrationAccessCtxKey string,
+) (endpoint, accessToken string, err error) {
+	grantToken, grantErr := ctx.GetGrantToken(grantTokenCtxKey)
+
+	access, accessErr := ctx.GetRegistrationAccess(registrationAccessCtxKey)
+	if accessErr == nil && access.Enabled() {
+		if grantErr == nil && grantToken.AccessToken == "" {
+			return access.ClientURI, "", nil
+		}
+		return access.ClientURI, access.AccessToken, nil
+	}
+
+	if grantErr != nil {
+		return "", "", grantErr
+	}
+	return fmt.Sprintf("%s/%s", registrationEndpoint, clientId), grantToken.AccessToken, nil
+}
+
+// clientConfigurationError describes which credential is missing when clientConfiguration fails,
+// the registration_access_token when the ASPSP returned a registration_client_uri without it,
+// otherwise the client credentials grant token
+func clientConfigurationError(ctx Context, grantTokenCtxKey, registrationAccessCtxKey string, err error) string {
+	access, accessErr := ctx.GetRegistrationAccess(registrationAccessCtxKey)
+	if accessErr == nil && access.ClientURI != "" && access.AccessToken == "" {
+		return fmt.Sprintf(
+			"unable to find registration access token %s in context, registration_client_uri was returned without it "+
+				"and client grant token %s is not available: %v",
+			registrationAccessCtxKey,
+			grantTokenCtxKey,
+			err,
+		)
+	}
+	return fmt.Sprintf("unable to find client grant token %s in context: %v", grantTokenCtxKey, err)
+}
@@ -0,0 +1,73 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientConfiguration_UsesRegistrationEndpointAndGrantToken(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{AccessToken: "ccg"})
+
+	endpoint, token, err := clientConfiguration(ctx, "https://as/register", "id", "grantTokenKey", "accessKey")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://as/register/id", endpoint)
+	assert.Equal(t, "ccg", token)
+}
+
+func TestClientConfiguration_UsesRegistrationAccess(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{AccessToken: "ccg"})
+	ctx.SetRegistrationAccess("accessKey", auth.RegistrationAccess{
+		ClientURI:   "https://as/clients/abc",
+		AccessToken: "rat",
+	})
+
+	endpoint, token, err := clientConfiguration(ctx, "https://as/register", "id", "grantTokenKey", "accessKey")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://as/clients/abc", endpoint)
+	assert.Equal(t, "rat", token)
+}
+
+func TestClientConfiguration_RegistrationAccessDoesNotNeedGrantToken(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetRegistrationAccess("accessKey", auth.RegistrationAccess{
+		ClientURI:   "https://as/clients/abc",
+		AccessToken: "rat",
+	})
+
+	endpoint, token, err := clientConfiguration(ctx, "https://as/register", "id", "grantTokenKey", "accessKey")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://as/clients/abc", endpoint)
+	assert.Equal(t, "rat", token)
+}
+
+func TestClientConfiguration_InvalidGrantTokenAppliesToRegistrationAccess(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{})
+	ctx.SetRegistrationAccess("accessKey", auth.RegistrationAccess{
+		ClientURI:   "https://as/clients/abc",
+		AccessToken: "rat",
+	})
+
+	endpoint, token, err := clientConfiguration(ctx, "https://as/register", "id", "grantTokenKey", "accessKey")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://as/clients/abc", endpoint)
+	assert.Equal(t, "", token)
+}
+
+func TestClientConfiguration_FailsWithoutCredentials(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetRegistrationAccess("accessKey", auth.RegistrationAccess{})
+
+	_, _, err := clientConfiguration(ctx, "https://as/register", "id", "grantTokenKey", "accessKey")
+
+	assert.Equal(t, ErrKeyNotFoundInContext, err)
+}
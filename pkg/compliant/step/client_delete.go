@@ -7,20 +7,25 @@ import (
 )
 
 type clientDelete struct {
-	client               *http.Client
-	stepName             string
-	clientCtxKey         string
-	registrationEndpoint string
-	grantTokenCtxKey     string
+	client                   *http.Client
+	stepName                 string
+	clientCtxKey             string
+	registrationEndpoint     string
+	grantTokenCtxKey         string
+	registrationAccessCtxKey string
 }
 
-func NewClientDelete(registrationEndpoint, clientCtxKey, grantTokenCtxKey string, httpClient *http.Client) Step {
+func NewClientDelete(
+	registrationEndpoint, clientCtxKey, grantTokenCtxKey, registrationAccessCtxKey string,
+	httpClient *http.Client,
+) Step {
 	return clientDelete{
-		stepName:             "Software client delete",
-		client:               httpClient,
-		registrationEndpoint: registrationEndpoint,
-		clientCtxKey:         clientCtxKey,
-		grantTokenCtxKey:     grantTokenCtxKey,
+		stepName:                 "Software client delete",
+		client:                   httpClient,
+		registrationEndpoint:     registrationEndpoint,
+		clientCtxKey:             clientCtxKey,
+		grantTokenCtxKey:         grantTokenCtxKey,
+		registrationAccessCtxKey: registrationAccessCtxKey,
 	}
 }
 
@@ -32,18 +37,23 @@ func (s clientDelete) Run(ctx Context) Result {
 		return NewFailResult(s.stepName, fmt.Sprintf("unable to find client %s in context: %v", s.clientCtxKey, err))
 	}
 
-	url := fmt.Sprintf("%s/%s", s.registrationEndpoint, client.Id())
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	url, accessToken, err := clientConfiguration(
+		ctx,
+		s.registrationEndpoint,
+		client.Id(),
+		s.grantTokenCtxKey,
+		s.registrationAccessCtxKey,
+	)
 	if err != nil {
-		return NewFailResult(s.stepName, fmt.Sprintf("unable to create request %s: %v", url, err))
+		msg := fmt.Sprintf("unable to find client grant token %s in context: %v", s.grantTokenCtxKey, err)
+		return NewFailResult(s.stepName, msg)
 	}
 
-	grantToken, err := ctx.GetGrantToken(s.grantTokenCtxKey)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
 	if err != nil {
-		msg := fmt.Sprintf("unable to find client grant token %s in context: %v", s.grantTokenCtxKey, err)
-		return NewFailResult(s.stepName, msg)
+		return NewFailResult(s.stepName, fmt.Sprintf("unable to create request %s: %v", url, err))
 	}
-	req.Header.Set("Authorization", "Bearer "+grantToken.AccessToken)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
 
 	debug.Log(http2.DebugRequest(req))
 
@@ -52,6 +62,7 @@ func (s clientDelete) Run(ctx Context) Result {
 		return NewFailResult(s.stepName, fmt.Sprintf("unable to call endpoint %s: %v", url, err))
 	}
 
+	debug.LogInteractionID(res)
 	debug.Log(http2.DebugResponse(res))
 
 	if res.StatusCode != http.StatusNoContent {
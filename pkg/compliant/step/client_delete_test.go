@@ -29,7 +29,7 @@ func TestNewClientDelete(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", softClient)
 	ctx.SetGrantToken("clientGrantKey", auth.GrantToken{})
-	step := NewClientDelete(server.URL, "clientKey", "clientGrantKey", server.Client())
+	step := NewClientDelete(server.URL, "clientKey", "clientGrantKey", "registrationAccessKey", server.Client())
 
 	result := step.Run(ctx)
 
@@ -51,7 +51,7 @@ func TestNewClientDelete_Expects204(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", softClient)
 	ctx.SetGrantToken("clientGrantKey", auth.GrantToken{})
-	step := NewClientDelete(server.URL, "clientKey", "clientGrantKey", server.Client())
+	step := NewClientDelete(server.URL, "clientKey", "clientGrantKey", "registrationAccessKey", server.Client())
 
 	result := step.Run(ctx)
 
@@ -64,7 +64,7 @@ func TestNewClientDelete_HandlesCreateRequestError(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", softClient)
 	ctx.SetGrantToken("clientGrantKey", auth.GrantToken{})
-	step := NewClientDelete(string(rune(0x7f)), "clientKey", "clientGrantKey", &http.Client{})
+	step := NewClientDelete(string(rune(0x7f)), "clientKey", "clientGrantKey", "registrationAccessKey", &http.Client{})
 
 	result := step.Run(ctx)
 
@@ -81,7 +81,7 @@ func TestNewClientDelete_HandlesExecuteRequestError(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", softClient)
 	ctx.SetGrantToken("clientGrantKey", auth.GrantToken{})
-	step := NewClientDelete("localhost", "clientKey", "clientGrantKey", &http.Client{})
+	step := NewClientDelete("localhost", "clientKey", "clientGrantKey", "registrationAccessKey", &http.Client{})
 
 	result := step.Run(ctx)
 
@@ -96,7 +96,7 @@ func TestNewClientDelete_HandlesExecuteRequestError(t *testing.T) {
 func TestNewClientDelete_HandlesErrorForClientNotFound(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetGrantToken("clientGrantKey", auth.GrantToken{})
-	step := NewClientDelete("localhost", "clientKey", "clientGrantKey", &http.Client{})
+	step := NewClientDelete("localhost", "clientKey", "clientGrantKey", "registrationAccessKey", &http.Client{})
 
 	result := step.Run(ctx)
 
@@ -112,7 +112,7 @@ func TestNewClientDelete_HandlesErrorForGrantNotFound(t *testing.T) {
 	softClient := client.NewClientSecretBasic(clientID, "", clientSecret)
 	ctx := NewContext()
 	ctx.SetClient("clientKey", softClient)
-	step := NewClientDelete("localhost", "clientKey", "clientGrantKey", &http.Client{})
+	step := NewClientDelete("localhost", "clientKey", "clientGrantKey", "registrationAccessKey", &http.Client{})
 
 	result := step.Run(ctx)
 
@@ -61,6 +61,7 @@ func (s clientRegister) doJwtPostRequest(endpoint, jwtClaims string) (*http.Resp
 	if err != nil {
 		return nil, errors.Wrap(err, "making jose post request")
 	}
+	s.debug.LogInteractionID(response)
 	s.debug.Logf("request finished with response status code %d", response.StatusCode)
 
 	return response, nil
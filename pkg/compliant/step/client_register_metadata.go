@@ -0,0 +1,160 @@
+package step
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+type clientRegisterMetadata struct {
+	stepName        string
+	jwtClaimsCtxKey string
+	responseCtxKey  string
+}
+
+// NewClientRegisterMetadata compares the client metadata returned by the ASPSP with the metadata sent
+// on the registration request.
+// RFC7591 allows an ASPSP to replace requested values, DCR spec only tolerates this for grant types,
+// response types and scope (restricted to what the SSA allows), any other modification is a failure.
+func NewClientRegisterMetadata(jwtClaimsCtxKey, responseCtxKey string) Step {
+	return clientRegisterMetadata{
+		stepName:        "Validate client register response metadata",
+		jwtClaimsCtxKey: jwtClaimsCtxKey,
+		responseCtxKey:  responseCtxKey,
+	}
+}
+
+type clientMetadata struct {
+	RedirectURIs             []string `json:"redirect_uris"`
+	TokenEndpointAuthMethod  string   `json:"token_endpoint_auth_method"`
+	GrantTypes               []string `json:"grant_types"`
+	ResponseTypes            []string `json:"response_types"`
+	Scope                    string   `json:"scope"`
+	IdTokenSignedResponseAlg string   `json:"id_token_signed_response_alg"`
+	TLSClientAuthSubjectDn   string   `json:"tls_client_auth_subject_dn"`
+}
+
+func (s clientRegisterMetadata) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	debug.Logf("get jwt claims from ctx var: %s", s.jwtClaimsCtxKey)
+	jwtClaims, err := ctx.GetString(s.jwtClaimsCtxKey)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("getting jwt claims: %s", err.Error()), debug)
+	}
+
+	requested, err := requestedMetadata(jwtClaims)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, err.Error(), debug)
+	}
+
+	debug.Logf("get response object from ctx var: %s", s.responseCtxKey)
+	response, err := ctx.GetResponse(s.responseCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("getting response object from context: %s", err.Error())
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	body, bodyCopy, err := http2.DrainBody(response.Body)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("copy body from response: %s", err.Error()), debug)
+	}
+	response.Body = body
+
+	var registered clientMetadata
+	if err = json.NewDecoder(bodyCopy).Decode(&registered); err != nil {
+		return NewFailResultWithDebug(s.stepName, "decoding response: "+err.Error(), debug)
+	}
+
+	failures, warnings := compareMetadata(requested, registered)
+	for _, warning := range warnings {
+		debug.Logf("warning: %s", warning)
+	}
+	if len(failures) > 0 {
+		msg := "metadata modified by ASPSP: " + strings.Join(failures, ", ")
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	return NewPassResultWithWarnings(s.stepName, warnings, debug)
+}
+
+func requestedMetadata(jwtClaims string) (clientMetadata, error) {
+	parts := strings.Split(jwtClaims, ".")
+	if len(parts) != 3 {
+		return clientMetadata{}, errors.New("jwt claims are not a signed jwt")
+	}
+	payload, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return clientMetadata{}, errors.Wrap(err, "decoding jwt claims")
+	}
+	var requested clientMetadata
+	if err = json.Unmarshal(payload, &requested); err != nil {
+		return clientMetadata{}, errors.Wrap(err, "decoding jwt claims")
+	}
+	return requested, nil
+}
+
+func compareMetadata(requested, registered clientMetadata) (failures, warnings []string) {
+	if !sameValues(requested.RedirectURIs, registered.RedirectURIs) {
+		failures = append(failures, modified("redirect_uris", requested.RedirectURIs, registered.RedirectURIs))
+	}
+	if requested.TokenEndpointAuthMethod != registered.TokenEndpointAuthMethod {
+		failures = append(failures, modified(
+			"token_endpoint_auth_method",
+			requested.TokenEndpointAuthMethod,
+			registered.TokenEndpointAuthMethod,
+		))
+	}
+	if requested.IdTokenSignedResponseAlg != registered.IdTokenSignedResponseAlg {
+		failures = append(failures, modified(
+			"id_token_signed_response_alg",
+			requested.IdTokenSignedResponseAlg,
+			registered.IdTokenSignedResponseAlg,
+		))
+	}
+	if requested.TLSClientAuthSubjectDn != "" && requested.TLSClientAuthSubjectDn != registered.TLSClientAuthSubjectDn {
+		failures = append(failures, modified(
+			"tls_client_auth_subject_dn",
+			requested.TLSClientAuthSubjectDn,
+			registered.TLSClientAuthSubjectDn,
+		))
+	}
+
+	if !sameValues(requested.GrantTypes, registered.GrantTypes) {
+		warnings = append(warnings, modified("grant_types", requested.GrantTypes, registered.GrantTypes))
+	}
+	if requested.ResponseTypes != nil && !sameValues(requested.ResponseTypes, registered.ResponseTypes) {
+		warnings = append(warnings, modified("response_types", requested.ResponseTypes, registered.ResponseTypes))
+	}
+	if !sameValues(strings.Fields(requested.Scope), strings.Fields(registered.Scope)) {
+		warnings = append(warnings, modified("scope", requested.Scope, registered.Scope))
+	}
+
+	return failures, warnings
+}
+
+func modified(field string, requested, registered interface{}) string {
+	return fmt.Sprintf("%s requested %v but registered %v", field, requested, registered)
+}
+
+// sameValues compares two lists ignoring order
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for key := range sortedA {
+		if sortedA[key] != sortedB[key] {
+			return false
+		}
+	}
+	return true
+}
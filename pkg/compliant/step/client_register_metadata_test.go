@@ -0,0 +1,106 @@
+package step
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientRegisterMetadata(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{
+		"redirect_uris":                []string{"https://one", "https://two"},
+		"token_endpoint_auth_method":   "tls_client_auth",
+		"grant_types":                  []string{"client_credentials"},
+		"scope":                        "accounts openid",
+		"id_token_signed_response_alg": "PS256",
+	}))
+	body := `{
+		"redirect_uris": ["https://two", "https://one"],
+		"token_endpoint_auth_method": "tls_client_auth",
+		"grant_types": ["client_credentials"],
+		"scope": "openid accounts",
+		"id_token_signed_response_alg": "PS256"
+	}`
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))})
+	step := NewClientRegisterMetadata("jwtClaimsCtxKey", "responseCtxKey")
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Validate client register response metadata", result.Name)
+	assert.Empty(t, result.Warnings)
+
+	response, err := ctx.GetResponse("responseCtxKey")
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(content))
+}
+
+func TestNewClientRegisterMetadata_WarnsOnModifiedScope(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{
+		"token_endpoint_auth_method": "private_key_jwt",
+		"scope":                      "accounts payments openid",
+	}))
+	body := `{"token_endpoint_auth_method": "private_key_jwt", "scope": "accounts openid"}`
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))})
+	step := NewClientRegisterMetadata("jwtClaimsCtxKey", "responseCtxKey")
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, []string{"scope requested accounts payments openid but registered accounts openid"}, result.Warnings)
+}
+
+func TestNewClientRegisterMetadata_FailsOnModifiedAuthMethod(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{
+		"token_endpoint_auth_method": "private_key_jwt",
+	}))
+	body := `{"token_endpoint_auth_method": "client_secret_basic"}`
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))})
+	step := NewClientRegisterMetadata("jwtClaimsCtxKey", "responseCtxKey")
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"metadata modified by ASPSP: token_endpoint_auth_method requested private_key_jwt but registered client_secret_basic",
+		result.FailReason,
+	)
+}
+
+func TestNewClientRegisterMetadata_FailsMissingClaims(t *testing.T) {
+	ctx := NewContext()
+	step := NewClientRegisterMetadata("jwtClaimsCtxKey", "responseCtxKey")
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "getting jwt claims: key not found in context", result.FailReason)
+}
+
+func TestNewClientRegisterMetadata_FailsMissingResponse(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{}))
+	step := NewClientRegisterMetadata("jwtClaimsCtxKey", "responseCtxKey")
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "getting response object from context: key not found in context", result.FailReason)
+}
+
+func unsignedClaims(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	return token
+}
@@ -1,6 +1,7 @@
 package step
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 
@@ -8,20 +9,25 @@ import (
 )
 
 type clientRegisterResponse struct {
-	stepName          string
-	responseCtxKey    string
-	clientCtxKey      string
-	debug             *DebugMessages
-	authoriserBuilder auth.AuthoriserBuilder
+	stepName                 string
+	responseCtxKey           string
+	clientCtxKey             string
+	registrationAccessCtxKey string
+	debug                    *DebugMessages
+	authoriserBuilder        auth.AuthoriserBuilder
 }
 
-func NewClientRegisterResponse(responseCtxKey, clientCtxKey string, authoriserBuilder auth.AuthoriserBuilder) Step {
+func NewClientRegisterResponse(
+	responseCtxKey, clientCtxKey, registrationAccessCtxKey string,
+	authoriserBuilder auth.AuthoriserBuilder,
+) Step {
 	return clientRegisterResponse{
-		stepName:          "Decode client register response",
-		responseCtxKey:    responseCtxKey,
-		clientCtxKey:      clientCtxKey,
-		debug:             NewDebug(),
-		authoriserBuilder: authoriserBuilder,
+		stepName:                 "Decode client register response",
+		responseCtxKey:           responseCtxKey,
+		clientCtxKey:             clientCtxKey,
+		registrationAccessCtxKey: registrationAccessCtxKey,
+		debug:                    NewDebug(),
+		authoriserBuilder:        authoriserBuilder,
 	}
 }
 
@@ -55,6 +61,20 @@ func (s clientRegisterResponse) Run(ctx Context) Result {
 	s.debug.Logf("setting software client in context var: %s", s.clientCtxKey)
 	ctx.SetClient(s.clientCtxKey, client)
 
+	var registrationResponse auth.OBClientRegistrationResponse
+	if err = json.Unmarshal(body, &registrationResponse); err != nil {
+		return s.failResult(fmt.Sprintf("client register: %s", err.Error()))
+	}
+	access := auth.RegistrationAccess{
+		ClientURI:   registrationResponse.RegistrationClientURI,
+		AccessToken: registrationResponse.RegistrationAccessToken,
+	}
+	if access.Enabled() {
+		s.debug.Log("registration_client_uri and registration_access_token returned, using RFC7592 client management")
+	}
+	s.debug.Logf("setting registration access in context var: %s", s.registrationAccessCtxKey)
+	ctx.SetRegistrationAccess(s.registrationAccessCtxKey, access)
+
 	return NewPassResultWithDebug(s.stepName, s.debug)
 }
 
@@ -29,7 +29,7 @@ func TestNewClientRegisterResponse(t *testing.T) {
 	ctx := NewContext()
 	body := ioutil.NopCloser(strings.NewReader(`{"client_id": "12345", "client_secret": "54321"}`))
 	ctx.SetResponse("response", &http.Response{Body: body})
-	step := NewClientRegisterResponse("response", "clientCtxKey", authoriserBuilder)
+	step := NewClientRegisterResponse("response", "clientCtxKey", "registrationAccessCtxKey", authoriserBuilder)
 
 	result := step.Run(ctx)
 
@@ -37,7 +37,7 @@ func TestNewClientRegisterResponse(t *testing.T) {
 	assert.Equal(t, "Decode client register response", result.Name)
 	client, err := ctx.GetClient("clientCtxKey")
 	require.NoError(t, err)
-	r, err := client.CredentialsGrantRequest()
+	r, err := client.CredentialsGrantRequest("")
 	require.NoError(t, err)
 	assert.Equal(t, "12345", client.Id())
 
@@ -55,7 +55,7 @@ func TestNewClientRegisterResponse_FailsIfResponseNotFoundInContext(t *testing.T
 		WithOpenIDConfig(openIdConfig).
 		WithJwtExpiration(time.Hour)
 	ctx := NewContext()
-	step := NewClientRegisterResponse("response", "clientCtxKey", authoriserBuilder)
+	step := NewClientRegisterResponse("response", "clientCtxKey", "registrationAccessCtxKey", authoriserBuilder)
 
 	result := step.Run(ctx)
 
@@ -76,7 +76,7 @@ func TestNewClientRegisterResponse_HandlesParsingResponseObject(t *testing.T) {
 	ctx := NewContext()
 	body := ioutil.NopCloser(strings.NewReader(`invalid json`))
 	ctx.SetResponse("response", &http.Response{Body: body})
-	step := NewClientRegisterResponse("response", "clientCtxKey", authoriserBuilder)
+	step := NewClientRegisterResponse("response", "clientCtxKey", "registrationAccessCtxKey", authoriserBuilder)
 
 	result := step.Run(ctx)
 
@@ -87,3 +87,32 @@ func TestNewClientRegisterResponse_HandlesParsingResponseObject(t *testing.T) {
 		result.FailReason,
 	)
 }
+
+func TestNewClientRegisterResponse_SetsRegistrationAccess(t *testing.T) {
+	openIdConfig := openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"client_secret_basic"}}
+	authoriserBuilder := auth.NewAuthoriserBuilder().
+		WithIssuer("softwareID").
+		WithKID("kid").
+		WithSSA("ssa").
+		WithPrivateKey(generateKey(t)).
+		WithTokenEndpointAuthMethod(jwt.SigningMethodPS256).
+		WithOpenIDConfig(openIdConfig).
+		WithJwtExpiration(time.Hour)
+	ctx := NewContext()
+	body := ioutil.NopCloser(strings.NewReader(`{
+		"client_id": "12345",
+		"registration_client_uri": "https://as/register/12345",
+		"registration_access_token": "rat"
+	}`))
+	ctx.SetResponse("response", &http.Response{Body: body})
+	step := NewClientRegisterResponse("response", "clientCtxKey", "registrationAccessCtxKey", authoriserBuilder)
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	access, err := ctx.GetRegistrationAccess("registrationAccessCtxKey")
+	require.NoError(t, err)
+	assert.True(t, access.Enabled())
+	assert.Equal(t, "https://as/register/12345", access.ClientURI)
+	assert.Equal(t, "rat", access.AccessToken)
+}
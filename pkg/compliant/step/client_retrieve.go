@@ -7,25 +7,27 @@ import (
 )
 
 type clientRetrieve struct {
-	client               *http.Client
-	stepName             string
-	clientCtxKey         string
-	grantTokenCtxKey     string
-	registrationEndpoint string
-	responseCtxKey       string
+	client                   *http.Client
+	stepName                 string
+	clientCtxKey             string
+	grantTokenCtxKey         string
+	registrationAccessCtxKey string
+	registrationEndpoint     string
+	responseCtxKey           string
 }
 
 func NewClientRetrieve(
-	responseCtxKey, registrationEndpoint, clientCtxKey, grantTokenCtxKey string,
+	responseCtxKey, registrationEndpoint, clientCtxKey, grantTokenCtxKey, registrationAccessCtxKey string,
 	httpClient *http.Client,
 ) Step {
 	return clientRetrieve{
-		stepName:             "Software client retrieve",
-		client:               httpClient,
-		registrationEndpoint: registrationEndpoint,
-		responseCtxKey:       responseCtxKey,
-		clientCtxKey:         clientCtxKey,
-		grantTokenCtxKey:     grantTokenCtxKey,
+		stepName:                 "Software client retrieve",
+		client:                   httpClient,
+		registrationEndpoint:     registrationEndpoint,
+		responseCtxKey:           responseCtxKey,
+		clientCtxKey:             clientCtxKey,
+		grantTokenCtxKey:         grantTokenCtxKey,
+		registrationAccessCtxKey: registrationAccessCtxKey,
 	}
 }
 
@@ -38,19 +40,24 @@ func (s clientRetrieve) Run(ctx Context) Result {
 		return NewFailResultWithDebug(s.stepName, msg, debug)
 	}
 
-	grantToken, err := ctx.GetGrantToken(s.grantTokenCtxKey)
+	endpoint, accessToken, err := clientConfiguration(
+		ctx,
+		s.registrationEndpoint,
+		client.Id(),
+		s.grantTokenCtxKey,
+		s.registrationAccessCtxKey,
+	)
 	if err != nil {
 		msg := fmt.Sprintf("unable to find grant token %s in context: %v", s.grantTokenCtxKey, err)
 		return NewFailResultWithDebug(s.stepName, msg, debug)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", s.registrationEndpoint, client.Id())
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 	if err != nil {
 		msg := fmt.Sprintf("unable to make request: %s", err.Error())
 		return NewFailResultWithDebug(s.stepName, msg, debug)
 	}
-	req.Header.Set("Authorization", "Bearer "+grantToken.AccessToken)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
 
 	debug.Log(http2.DebugRequest(req))
 	res, err := s.client.Do(req)
@@ -59,6 +66,7 @@ func (s clientRetrieve) Run(ctx Context) Result {
 		return NewFailResultWithDebug(s.stepName, msg, debug)
 	}
 
+	debug.LogInteractionID(res)
 	ctx.SetResponse(s.responseCtxKey, res)
 	return NewPassResultWithDebug(s.stepName, debug)
 }
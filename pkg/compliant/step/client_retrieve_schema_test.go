@@ -3,6 +3,7 @@ package step
 import (
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -58,3 +59,35 @@ func (s *stubValidator) Validate(reader io.Reader) []schema.Failure {
 	s.called = true
 	return s.failures
 }
+
+func TestNewClientRetrieveSchema_KeepsResponseBodyReadable(t *testing.T) {
+	validator := &stubValidator{}
+	ctx := NewContext()
+	body := ioutil.NopCloser(strings.NewReader(`{"client_id": "12345"}`))
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: body})
+	step := NewClientRetrieveSchema("responseCtxKey", validator)
+
+	result := step.Run(ctx)
+
+	require.True(t, result.Pass)
+	response, err := ctx.GetResponse("responseCtxKey")
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"client_id": "12345"}`, string(content))
+}
+
+func TestNewClientRetrieveSchema_FailsOnResponseViolatingSchema(t *testing.T) {
+	validator, err := schema.NewValidator("3.2")
+	require.NoError(t, err)
+	ctx := NewContext()
+	body := ioutil.NopCloser(strings.NewReader(`{"client_id": "12345", "request_object_signing_alg": ["PS256"]}`))
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: body})
+	step := NewClientRetrieveSchema("responseCtxKey", validator)
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "schema invalid: ")
+	assert.Contains(t, result.FailReason, "request_object_signing_alg")
+}
@@ -26,7 +26,14 @@ func TestNewClientRetrieve(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, server.URL, clientSecret))
 	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{})
-	step := NewClientRetrieve("responseCtxKey", server.URL, "clientKey", "grantTokenKey", server.Client())
+	step := NewClientRetrieve(
+		"responseCtxKey",
+		server.URL,
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		server.Client(),
+	)
 
 	result := step.Run(ctx)
 
@@ -43,7 +50,14 @@ func TestNewClientRegister_HandlesMakeRequestError(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, "", clientSecret))
 	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{})
-	step := NewClientRetrieve("responseCtxKey", string(rune(0x7f)), "clientKey", "grantTokenKey", &http.Client{})
+	step := NewClientRetrieve(
+		"responseCtxKey",
+		string(rune(0x7f)),
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		&http.Client{},
+	)
 
 	result := step.Run(ctx)
 
@@ -59,7 +73,14 @@ func TestNewClientRegister_HandlesExecuteRequestError(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, "", clientSecret))
 	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{})
-	step := NewClientRetrieve("responseCtxKey", "localhost", "clientKey", "grantTokenKey", &http.Client{})
+	step := NewClientRetrieve(
+		"responseCtxKey",
+		"localhost",
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		&http.Client{},
+	)
 
 	result := step.Run(ctx)
 
@@ -78,7 +99,14 @@ func TestNewClientRegister_HandlesErrorForClientNotFound(t *testing.T) {
 		RegistrationEndpoint: &registrationEndpoint,
 		TokenEndpoint:        "",
 	})
-	step := NewClientRetrieve("responseCtxKey", "localhost", "clientKey", "grantTokenKey", &http.Client{})
+	step := NewClientRetrieve(
+		"responseCtxKey",
+		"localhost",
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		&http.Client{},
+	)
 
 	result := step.Run(ctx)
 
@@ -93,7 +121,14 @@ func TestNewClientRegister_HandlesErrorForClientNotFound(t *testing.T) {
 func TestNewClientRegister_HandlesErrorForGrantTokenNotFound(t *testing.T) {
 	ctx := NewContext()
 	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, "", clientSecret))
-	step := NewClientRetrieve("responseCtxKey", "localhost", "clientKey", "grantTokenKey", &http.Client{})
+	step := NewClientRetrieve(
+		"responseCtxKey",
+		"localhost",
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		&http.Client{},
+	)
 
 	result := step.Run(ctx)
 
@@ -104,3 +139,33 @@ func TestNewClientRegister_HandlesErrorForGrantTokenNotFound(t *testing.T) {
 		result.FailReason,
 	)
 }
+
+func TestNewClientRetrieve_UsesRegistrationAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/clients/abc", r.URL.EscapedPath())
+		assert.Equal(t, "Bearer rat", r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(`OK`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, server.URL, clientSecret))
+	ctx.SetGrantToken("grantTokenKey", auth.GrantToken{AccessToken: "ccg"})
+	ctx.SetRegistrationAccess("registrationAccessKey", auth.RegistrationAccess{
+		ClientURI:   server.URL + "/clients/abc",
+		AccessToken: "rat",
+	})
+	step := NewClientRetrieve(
+		"responseCtxKey",
+		server.URL,
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		server.Client(),
+	)
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+}
@@ -2,6 +2,7 @@ package step
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
 	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
@@ -10,14 +11,15 @@ import (
 )
 
 type clientUpdate struct {
-	stepName             string
-	client               *http.Client
-	registrationEndpoint string
-	responseCtxKey       string
-	jwtClaimsCtxKey      string
-	clientCtxKey         string
-	grantTokenCtxKey     string
-	debug                *DebugMessages
+	stepName                 string
+	client                   *http.Client
+	registrationEndpoint     string
+	responseCtxKey           string
+	jwtClaimsCtxKey          string
+	clientCtxKey             string
+	grantTokenCtxKey         string
+	registrationAccessCtxKey string
+	debug                    *DebugMessages
 }
 
 func NewClientUpdate(
@@ -25,18 +27,20 @@ func NewClientUpdate(
 	jwtClaimsCtxKey,
 	responseCtxKey,
 	clientCtxKey,
-	grantTokenCtxKey string,
+	grantTokenCtxKey,
+	registrationAccessCtxKey string,
 	httpClient *http.Client,
 ) Step {
 	return clientUpdate{
-		stepName:             "Software client update",
-		registrationEndpoint: registrationEndpoint,
-		client:               httpClient,
-		jwtClaimsCtxKey:      jwtClaimsCtxKey,
-		responseCtxKey:       responseCtxKey,
-		clientCtxKey:         clientCtxKey,
-		grantTokenCtxKey:     grantTokenCtxKey,
-		debug:                NewDebug(),
+		stepName:                 "Software client update",
+		registrationEndpoint:     registrationEndpoint,
+		client:                   httpClient,
+		jwtClaimsCtxKey:          jwtClaimsCtxKey,
+		responseCtxKey:           responseCtxKey,
+		clientCtxKey:             clientCtxKey,
+		grantTokenCtxKey:         grantTokenCtxKey,
+		registrationAccessCtxKey: registrationAccessCtxKey,
+		debug:                    NewDebug(),
 	}
 }
 
@@ -53,25 +57,68 @@ func (s clientUpdate) Run(ctx Context) Result {
 		return NewFailResultWithDebug(s.stepName, msg, s.debug)
 	}
 
-	grantToken, err := ctx.GetGrantToken(s.grantTokenCtxKey)
+	endpoint, accessToken, err := clientConfiguration(
+		ctx,
+		s.registrationEndpoint,
+		client.Id(),
+		s.grantTokenCtxKey,
+		s.registrationAccessCtxKey,
+	)
 	if err != nil {
-		msg := fmt.Sprintf("unable to find client grant token %s in context: %v", s.grantTokenCtxKey, err)
+		msg := clientConfigurationError(ctx, s.grantTokenCtxKey, s.registrationAccessCtxKey, err)
 		return NewFailResultWithDebug(s.stepName, msg, s.debug)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", s.registrationEndpoint, client.Id())
-	response, err := s.doJwtPutRequest(endpoint, jwtClaims, grantToken)
+	response, err := s.doJwtPutRequest(endpoint, jwtClaims, accessToken)
 	if err != nil {
 		return s.failResult(err.Error())
 	}
 
+	if response.StatusCode == http.StatusOK {
+		if err = s.refreshRegistrationAccess(ctx, response); err != nil {
+			return s.failResult(err.Error())
+		}
+	}
+
 	s.debug.Logf("setting response object in context var: %s", s.responseCtxKey)
 	ctx.SetResponse(s.responseCtxKey, response)
 
 	return NewPassResultWithDebug(s.stepName, s.debug)
 }
 
-func (s clientUpdate) doJwtPutRequest(endpoint, jwtClaims string, grantToken auth.GrantToken) (*http.Response, error) {
+// refreshRegistrationAccess stores the registration_access_token and registration_client_uri
+// an ASPSP can rotate in the update response (RFC7592 2.2), so the next requests use the current ones
+func (s clientUpdate) refreshRegistrationAccess(ctx Context, response *http.Response) error {
+	access, err := ctx.GetRegistrationAccess(s.registrationAccessCtxKey)
+	if err != nil || !access.Enabled() {
+		return nil
+	}
+
+	body, bodyCopy, err := http2.DrainBody(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "copy body from update response")
+	}
+	response.Body = body
+
+	var updated auth.OBClientRegistrationResponse
+	if err = json.NewDecoder(bodyCopy).Decode(&updated); err != nil {
+		return errors.Wrap(err, "decoding update response")
+	}
+	if updated.RegistrationAccessToken != "" && updated.RegistrationAccessToken != access.AccessToken {
+		s.debug.Log("registration_access_token rotated in update response")
+		access.AccessToken = updated.RegistrationAccessToken
+	}
+	if updated.RegistrationClientURI != "" && updated.RegistrationClientURI != access.ClientURI {
+		s.debug.Logf("registration_client_uri changed in update response to %s", updated.RegistrationClientURI)
+		access.ClientURI = updated.RegistrationClientURI
+	}
+
+	s.debug.Logf("setting registration access in context var: %s", s.registrationAccessCtxKey)
+	ctx.SetRegistrationAccess(s.registrationAccessCtxKey, access)
+	return nil
+}
+
+func (s clientUpdate) doJwtPutRequest(endpoint, jwtClaims, accessToken string) (*http.Response, error) {
 	body := bytes.NewBufferString(jwtClaims)
 	req, err := http.NewRequest(http.MethodPut, endpoint, body)
 	if err != nil {
@@ -80,7 +127,7 @@ func (s clientUpdate) doJwtPutRequest(endpoint, jwtClaims string, grantToken aut
 	req.Header.Add("Content-Type", "application/jose")
 	req.Header.Add("Accept", "application/json")
 
-	req.Header.Set("Authorization", "Bearer "+grantToken.AccessToken)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
 
 	s.debug.Log(http2.DebugRequest(req))
 
@@ -89,6 +136,7 @@ func (s clientUpdate) doJwtPutRequest(endpoint, jwtClaims string, grantToken aut
 	if err != nil {
 		return nil, errors.Wrap(err, "making jose put request")
 	}
+	s.debug.LogInteractionID(response)
 	s.debug.Logf("request finished with response status code %d", response.StatusCode)
 
 	return response, nil
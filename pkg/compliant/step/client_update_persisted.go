@@ -0,0 +1,98 @@
+package step
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type clientUpdatePersisted struct {
+	stepName        string
+	jwtClaimsCtxKey string
+	responseCtxKey  string
+	clientCtxKey    string
+	scopeUpdated    bool
+}
+
+// NewClientUpdatePersisted asserts that a client retrieved after a PUT reflects the updated
+// redirect_uris and still has the same client_id.
+// As on registration, a scope the ASPSP restricted to what the SSA allows is only a warning,
+// unless scope is the field the update changed, then it must be persisted.
+func NewClientUpdatePersisted(jwtClaimsCtxKey, responseCtxKey, clientCtxKey string, scopeUpdated bool) Step {
+	return clientUpdatePersisted{
+		stepName:        "Validate client update is persisted",
+		jwtClaimsCtxKey: jwtClaimsCtxKey,
+		responseCtxKey:  responseCtxKey,
+		clientCtxKey:    clientCtxKey,
+		scopeUpdated:    scopeUpdated,
+	}
+}
+
+type retrievedClientMetadata struct {
+	ClientID string `json:"client_id"`
+	clientMetadata
+}
+
+func (s clientUpdatePersisted) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	debug.Logf("get jwt claims from ctx var: %s", s.jwtClaimsCtxKey)
+	jwtClaims, err := ctx.GetString(s.jwtClaimsCtxKey)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("getting jwt claims: %s", err.Error()), debug)
+	}
+
+	updated, err := requestedMetadata(jwtClaims)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, err.Error(), debug)
+	}
+
+	client, err := ctx.GetClient(s.clientCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("unable to find client %s in context: %v", s.clientCtxKey, err)
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	debug.Logf("get response object from ctx var: %s", s.responseCtxKey)
+	response, err := ctx.GetResponse(s.responseCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("getting response object from context: %s", err.Error())
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	body, bodyCopy, err := http2.DrainBody(response.Body)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("copy body from response: %s", err.Error()), debug)
+	}
+	response.Body = body
+
+	var retrieved retrievedClientMetadata
+	if err = json.NewDecoder(bodyCopy).Decode(&retrieved); err != nil {
+		return NewFailResultWithDebug(s.stepName, "decoding response: "+err.Error(), debug)
+	}
+
+	var failures []string
+	if retrieved.ClientID != client.Id() {
+		failures = append(failures, fmt.Sprintf("client_id changed from %s to %s", client.Id(), retrieved.ClientID))
+	}
+	if !sameValues(updated.RedirectURIs, retrieved.RedirectURIs) {
+		failures = append(failures, modified("redirect_uris", updated.RedirectURIs, retrieved.RedirectURIs))
+	}
+	scopePersisted := sameValues(strings.Fields(updated.Scope), strings.Fields(retrieved.Scope))
+	if s.scopeUpdated && !scopePersisted {
+		failures = append(failures, modified("scope", updated.Scope, retrieved.Scope))
+	}
+	if len(failures) > 0 {
+		msg := "client update not persisted: " + strings.Join(failures, ", ")
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	if !scopePersisted {
+		warnings := []string{modified("scope", updated.Scope, retrieved.Scope)}
+		return NewPassResultWithWarnings(s.stepName, warnings, debug)
+	}
+
+	return NewPassResultWithDebug(s.stepName, debug)
+}
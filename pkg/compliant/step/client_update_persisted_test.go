@@ -0,0 +1,105 @@
+package step
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientUpdatePersisted(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{
+		"redirect_uris": []string{"https://one"},
+		"scope":         "openid",
+	}))
+	ctx.SetClient("clientCtxKey", client.NewClientSecretBasic("12345", "secret", "/token"))
+	body := `{"client_id": "12345", "redirect_uris": ["https://one"], "scope": "openid"}`
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))})
+	step := NewClientUpdatePersisted("jwtClaimsCtxKey", "responseCtxKey", "clientCtxKey", false)
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Validate client update is persisted", result.Name)
+	assert.Equal(t, "", result.FailReason)
+}
+
+func TestNewClientUpdatePersisted_FailsOnStaleMetadata(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{
+		"redirect_uris": []string{"https://one"},
+		"scope":         "accounts openid",
+	}))
+	ctx.SetClient("clientCtxKey", client.NewClientSecretBasic("12345", "secret", "/token"))
+	body := `{"client_id": "54321", "redirect_uris": ["https://one", "https://two"], "scope": "accounts openid"}`
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))})
+	step := NewClientUpdatePersisted("jwtClaimsCtxKey", "responseCtxKey", "clientCtxKey", false)
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"client update not persisted: client_id changed from 12345 to 54321, "+
+			"redirect_uris requested [https://one] but registered [https://one https://two]",
+		result.FailReason,
+	)
+}
+
+func TestNewClientUpdatePersisted_WarnsOnModifiedScope(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{
+		"redirect_uris": []string{"https://one"},
+		"scope":         "accounts payments openid",
+	}))
+	ctx.SetClient("clientCtxKey", client.NewClientSecretBasic("12345", "secret", "/token"))
+	body := `{"client_id": "12345", "redirect_uris": ["https://one"], "scope": "accounts openid"}`
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))})
+	step := NewClientUpdatePersisted("jwtClaimsCtxKey", "responseCtxKey", "clientCtxKey", false)
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(
+		t,
+		[]string{"scope requested accounts payments openid but registered accounts openid"},
+		result.Warnings,
+	)
+}
+
+func TestNewClientUpdatePersisted_FailsOnScopeNotPersistedWhenUpdated(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{
+		"redirect_uris": []string{"https://one"},
+		"scope":         "openid",
+	}))
+	ctx.SetClient("clientCtxKey", client.NewClientSecretBasic("12345", "secret", "/token"))
+	body := `{"client_id": "12345", "redirect_uris": ["https://one"], "scope": "accounts openid"}`
+	ctx.SetResponse("responseCtxKey", &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))})
+	step := NewClientUpdatePersisted("jwtClaimsCtxKey", "responseCtxKey", "clientCtxKey", true)
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"client update not persisted: scope requested openid but registered accounts openid",
+		result.FailReason,
+	)
+}
+
+func TestNewClientUpdatePersisted_FailsMissingClient(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsCtxKey", unsignedClaims(t, jwt.MapClaims{}))
+	step := NewClientUpdatePersisted("jwtClaimsCtxKey", "responseCtxKey", "clientCtxKey", false)
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "unable to find client clientCtxKey in context: key not found in context", result.FailReason)
+}
@@ -0,0 +1,98 @@
+package step
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientUpdate_RefreshesRotatedRegistrationAccess(t *testing.T) {
+	body := `{"client_id": "foo", "registration_access_token": "rotated", "registration_client_uri": "https://as/new"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/clients/foo", r.URL.Path)
+		assert.Equal(t, "Bearer registration", r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsKey", "jwt")
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, "/token"))
+	ctx.SetRegistrationAccess(
+		"registrationAccessKey",
+		auth.RegistrationAccess{ClientURI: server.URL + "/clients/foo", AccessToken: "registration"},
+	)
+	step := NewClientUpdate(
+		server.URL,
+		"jwtClaimsKey",
+		"responseKey",
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		server.Client(),
+	)
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	access, err := ctx.GetRegistrationAccess("registrationAccessKey")
+	require.NoError(t, err)
+	assert.Equal(t, auth.RegistrationAccess{ClientURI: "https://as/new", AccessToken: "rotated"}, access)
+	response, err := ctx.GetResponse("responseKey")
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(content), "response body should still be readable")
+}
+
+func TestNewClientUpdate_FailsOnMissingRegistrationAccessToken(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsKey", "jwt")
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, "/token"))
+	ctx.SetRegistrationAccess("registrationAccessKey", auth.RegistrationAccess{ClientURI: "https://as/clients/foo"})
+	step := NewClientUpdate(
+		"https://as/register",
+		"jwtClaimsKey",
+		"responseKey",
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		&http.Client{},
+	)
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "unable to find registration access token registrationAccessKey in context")
+}
+
+func TestNewClientUpdate_FailsOnMissingGrantToken(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaimsKey", "jwt")
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, "/token"))
+	step := NewClientUpdate(
+		"https://as/register",
+		"jwtClaimsKey",
+		"responseKey",
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		&http.Client{},
+	)
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"unable to find client grant token grantTokenKey in context: key not found in context",
+		result.FailReason,
+	)
+}
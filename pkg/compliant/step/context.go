@@ -21,6 +21,8 @@ type Context interface {
 	GetClient(key string) (dcr.Client, error)
 	SetGrantToken(key string, token auth.GrantToken)
 	GetGrantToken(key string) (auth.GrantToken, error)
+	SetRegistrationAccess(key string, access auth.RegistrationAccess)
+	GetRegistrationAccess(key string) (auth.RegistrationAccess, error)
 }
 
 var ErrKeyNotFoundInContext = errors.New("key not found in context")
@@ -32,6 +34,7 @@ type context struct {
 	openIdConfigs map[string]openid.Configuration
 	clients       map[string]dcr.Client
 	grantTokens   map[string]auth.GrantToken
+	regAccesses   map[string]auth.RegistrationAccess
 }
 
 func NewContext() Context {
@@ -42,6 +45,7 @@ func NewContext() Context {
 		openIdConfigs: map[string]openid.Configuration{},
 		clients:       map[string]dcr.Client{},
 		grantTokens:   map[string]auth.GrantToken{},
+		regAccesses:   map[string]auth.RegistrationAccess{},
 	}
 }
 
@@ -116,3 +120,15 @@ func (c *context) GetGrantToken(key string) (auth.GrantToken, error) {
 	}
 	return value, nil
 }
+
+func (c *context) SetRegistrationAccess(key string, access auth.RegistrationAccess) {
+	c.regAccesses[key] = access
+}
+
+func (c *context) GetRegistrationAccess(key string) (auth.RegistrationAccess, error) {
+	value, ok := c.regAccesses[key]
+	if !ok {
+		return auth.RegistrationAccess{}, ErrKeyNotFoundInContext
+	}
+	return value, nil
+}
@@ -1,6 +1,7 @@
 package step
 
 import (
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
 	dcr "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"net/http"
@@ -123,3 +124,22 @@ func TestContext_GetClient_ReturnsError_IfDoesntExists(t *testing.T) {
 
 	assert.Equal(t, ErrKeyNotFoundInContext, err)
 }
+
+func TestContext_SetRegistrationAccess(t *testing.T) {
+	ctx := NewContext()
+	access := auth.RegistrationAccess{ClientURI: "https://as/register/id", AccessToken: "token"}
+	ctx.SetRegistrationAccess("key", access)
+
+	value, err := ctx.GetRegistrationAccess("key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, access, value)
+}
+
+func TestContext_GetRegistrationAccess_ReturnsError_IfDoesntExists(t *testing.T) {
+	ctx := NewContext()
+
+	_, err := ctx.GetRegistrationAccess("non existing key")
+
+	assert.Equal(t, ErrKeyNotFoundInContext, err)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
 	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
@@ -14,15 +15,27 @@ type clientCredentialsGrant struct {
 	grantTokenCtxKey string
 	clientCtxKey     string
 	tokenEndpoint    string
+	scopes           []string
 	stepName         string
 }
 
 func NewClientCredentialsGrant(grantTokenCtxKey, clientCtxKey, tokenEndpoint string, httpClient *http.Client) Step {
+	return NewClientCredentialsGrantWithScope(grantTokenCtxKey, clientCtxKey, tokenEndpoint, nil, httpClient)
+}
+
+// NewClientCredentialsGrantWithScope requests a client credentials grant for the given scopes
+// and checks the token response does not grant any scope that was not requested
+func NewClientCredentialsGrantWithScope(
+	grantTokenCtxKey, clientCtxKey, tokenEndpoint string,
+	scopes []string,
+	httpClient *http.Client,
+) Step {
 	return clientCredentialsGrant{
 		client:           httpClient,
 		grantTokenCtxKey: grantTokenCtxKey,
 		clientCtxKey:     clientCtxKey,
 		tokenEndpoint:    tokenEndpoint,
+		scopes:           scopes,
 		stepName:         fmt.Sprintf("Client credentials grant"),
 	}
 }
@@ -35,7 +48,7 @@ func (a clientCredentialsGrant) Run(ctx Context) Result {
 		msg := fmt.Sprintf("getting software client object from context: %s", err.Error())
 		return NewFailResultWithDebug(a.stepName, msg, debug)
 	}
-	r, err := softwareClient.CredentialsGrantRequest()
+	r, err := softwareClient.CredentialsGrantRequest(strings.Join(a.scopes, " "))
 	if err != nil {
 		msg := fmt.Sprintf("unable to build request object: %s", err.Error())
 		return NewFailResultWithDebug(a.stepName, msg, debug)
@@ -49,6 +62,7 @@ func (a clientCredentialsGrant) Run(ctx Context) Result {
 		message := fmt.Sprintf("error making token request call: %s", err.Error())
 		return NewFailResultWithDebug(a.stepName, message, debug)
 	}
+	debug.LogInteractionID(response)
 	debug.Log(http2.DebugResponse(response))
 
 	if response.StatusCode != http.StatusOK {
@@ -62,9 +76,36 @@ func (a clientCredentialsGrant) Run(ctx Context) Result {
 		return NewFailResultWithDebug(a.stepName, message, debug)
 	}
 
+	// RFC6749 section 5.1: scope is only required in the response when it differs from the requested one
+	if len(a.scopes) > 0 && credentialsGrantResponse.Scope != "" {
+		if extra := notRequested(strings.Fields(credentialsGrantResponse.Scope), a.scopes); len(extra) > 0 {
+			message := fmt.Sprintf("granted scope %s was not requested", strings.Join(extra, " "))
+			return NewFailResultWithDebug(a.stepName, message, debug)
+		}
+	}
+
 	token := auth.GrantToken(credentialsGrantResponse)
 	debug.Logf("setting client credentials token in context var: %s", a.grantTokenCtxKey)
 	ctx.SetGrantToken(a.grantTokenCtxKey, token)
 
 	return NewPassResultWithDebug(a.stepName, debug)
 }
+
+func notRequested(granted, requested []string) []string {
+	var extra []string
+	for _, scope := range granted {
+		if !containsValue(requested, scope) {
+			extra = append(extra, scope)
+		}
+	}
+	return extra
+}
+
+func containsValue(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
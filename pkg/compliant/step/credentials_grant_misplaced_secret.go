@@ -0,0 +1,71 @@
+package step
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type clientCredentialsGrantMisplacedSecret struct {
+	client       *http.Client
+	clientCtxKey string
+	stepName     string
+}
+
+// NewClientCredentialsGrantMisplacedSecret asserts a token endpoint rejects a client credentials grant
+// where the client secret is not sent using the registered auth method,
+// ie: in the request body for client_secret_basic or in the Authorization header for client_secret_post
+func NewClientCredentialsGrantMisplacedSecret(clientCtxKey string, httpClient *http.Client) Step {
+	return clientCredentialsGrantMisplacedSecret{
+		client:       httpClient,
+		clientCtxKey: clientCtxKey,
+		stepName:     "Client credentials grant rejects misplaced client secret",
+	}
+}
+
+func (a clientCredentialsGrantMisplacedSecret) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	softwareClient, err := ctx.GetClient(a.clientCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("getting software client object from context: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	misplacedClient, err := client.NewMisplacedSecretClient(softwareClient)
+	if err != nil {
+		return NewFailResultWithDebug(a.stepName, err.Error(), debug)
+	}
+
+	r, err := misplacedClient.CredentialsGrantRequest("")
+	if err != nil {
+		msg := fmt.Sprintf("unable to build request object: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	r.Header.Set("Content-type", "application/x-www-form-urlencoded")
+	debug.Log(http2.DebugRequest(r))
+
+	response, err := a.client.Do(r)
+	if err != nil {
+		message := fmt.Sprintf("error making token request call: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+	defer response.Body.Close()
+	debug.LogInteractionID(response)
+	debug.Log(http2.DebugResponse(response))
+
+	if response.StatusCode != http.StatusBadRequest && response.StatusCode != http.StatusUnauthorized {
+		message := fmt.Sprintf(
+			"unexpected status code %d, should be %d or %d",
+			response.StatusCode,
+			http.StatusBadRequest,
+			http.StatusUnauthorized,
+		)
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+
+	return NewPassResultWithDebug(a.stepName, debug)
+}
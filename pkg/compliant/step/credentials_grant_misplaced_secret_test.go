@@ -0,0 +1,61 @@
+package step
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCredentialsGrantMisplacedSecret_PassesWhenRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, clientSecret, r.PostForm.Get("client_secret"))
+		assert.Equal(t, "", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewClientCredentialsGrantMisplacedSecret("clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Client credentials grant rejects misplaced client secret", result.Name)
+}
+
+func TestClientCredentialsGrantMisplacedSecret_FailsWhenAccepted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, ok := r.BasicAuth()
+		assert.True(t, ok)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"access_token": "takeit"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretPost(clientID, clientSecret, server.URL))
+	step := NewClientCredentialsGrantMisplacedSecret("clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "unexpected status code 200, should be 400 or 401", result.FailReason)
+}
+
+func TestClientCredentialsGrantMisplacedSecret_FailsForNonSecretClient(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewNoClient())
+	step := NewClientCredentialsGrantMisplacedSecret("clientKey", &http.Client{})
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "client does not authenticate with a client secret", result.FailReason)
+}
@@ -0,0 +1,101 @@
+package step
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type clientCredentialsGrantRejected struct {
+	client       *http.Client
+	clientCtxKey string
+	invalid      client.InvalidGrant
+	stepName     string
+}
+
+// NewClientCredentialsGrantRejected asserts a token endpoint rejects an invalid client credentials grant request,
+// for a reused jti the first request is sent and only the replay must be rejected
+func NewClientCredentialsGrantRejected(
+	clientCtxKey string,
+	invalid client.InvalidGrant,
+	httpClient *http.Client,
+) Step {
+	return clientCredentialsGrantRejected{
+		client:       httpClient,
+		clientCtxKey: clientCtxKey,
+		invalid:      invalid,
+		stepName:     fmt.Sprintf("Client credentials grant rejects %s", invalid),
+	}
+}
+
+func (a clientCredentialsGrantRejected) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	softwareClient, err := ctx.GetClient(a.clientCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("getting software client object from context: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	invalidClient, err := client.NewInvalidGrantClient(softwareClient, a.invalid)
+	if err != nil {
+		return NewFailResultWithDebug(a.stepName, err.Error(), debug)
+	}
+
+	if a.invalid == client.ReusedAssertionJti {
+		debug.Log("sending first client credentials grant with the client assertion jti")
+		var firstStatusCode int
+		firstStatusCode, err = a.grant(invalidClient, debug)
+		if err != nil {
+			return NewFailResultWithDebug(a.stepName, err.Error(), debug)
+		}
+		// the replay is only meaningful if the first use of the jti was accepted
+		if firstStatusCode != http.StatusOK {
+			message := fmt.Sprintf(
+				"unexpected status code %d on first use of the client assertion jti, should be %d",
+				firstStatusCode,
+				http.StatusOK,
+			)
+			return NewFailResultWithDebug(a.stepName, message, debug)
+		}
+	}
+
+	statusCode, err := a.grant(invalidClient, debug)
+	if err != nil {
+		return NewFailResultWithDebug(a.stepName, err.Error(), debug)
+	}
+
+	if statusCode != http.StatusBadRequest && statusCode != http.StatusUnauthorized {
+		message := fmt.Sprintf(
+			"unexpected status code %d, should be %d or %d",
+			statusCode,
+			http.StatusBadRequest,
+			http.StatusUnauthorized,
+		)
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+
+	return NewPassResultWithDebug(a.stepName, debug)
+}
+
+func (a clientCredentialsGrantRejected) grant(invalidClient client.Client, debug *DebugMessages) (int, error) {
+	r, err := invalidClient.CredentialsGrantRequest("")
+	if err != nil {
+		return 0, fmt.Errorf("unable to build request object: %s", err.Error())
+	}
+
+	r.Header.Set("Content-type", "application/x-www-form-urlencoded")
+	debug.Log(http2.DebugRequest(r))
+
+	response, err := a.client.Do(r)
+	if err != nil {
+		return 0, fmt.Errorf("error making token request call: %s", err.Error())
+	}
+	defer response.Body.Close()
+	debug.LogInteractionID(response)
+	debug.Log(http2.DebugResponse(response))
+
+	return response.StatusCode, nil
+}
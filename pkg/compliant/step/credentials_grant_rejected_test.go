@@ -0,0 +1,99 @@
+package step
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCredentialsGrantRejected_PassesWhenRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "password", r.PostForm.Get("grant_type"))
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewClientCredentialsGrantRejected("clientKey", client.UnregisteredGrantType, server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Client credentials grant rejects grant type not registered", result.Name)
+}
+
+func TestClientCredentialsGrantRejected_ReusedJtiRejectsReplay(t *testing.T) {
+	var assertions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assertions = append(assertions, r.PostForm.Get("client_assertion"))
+		if len(assertions) > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretJwt(clientID, clientSecret, server.URL))
+	step := NewClientCredentialsGrantRejected("clientKey", client.ReusedAssertionJti, server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Len(t, assertions, 2)
+}
+
+func TestClientCredentialsGrantRejected_ReusedJtiFailsWhenFirstGrantRejected(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretJwt(clientID, clientSecret, server.URL))
+	step := NewClientCredentialsGrantRejected("clientKey", client.ReusedAssertionJti, server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"unexpected status code 401 on first use of the client assertion jti, should be 200",
+		result.FailReason,
+	)
+	assert.Equal(t, 1, requests)
+}
+
+func TestClientCredentialsGrantRejected_FailsWhenAccepted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretJwt(clientID, clientSecret, server.URL))
+	step := NewClientCredentialsGrantRejected("clientKey", client.ExpiredAssertion, server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "Client credentials grant rejects expired client assertion", result.Name)
+	assert.Equal(t, "unexpected status code 200, should be 400 or 401", result.FailReason)
+}
+
+func TestClientCredentialsGrantRejected_FailsWithoutClientAssertion(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, "http://token"))
+	step := NewClientCredentialsGrantRejected("clientKey", client.WrongAssertionType, &http.Client{})
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "client does not authenticate with a client assertion", result.FailReason)
+}
@@ -0,0 +1,100 @@
+package step
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+// maxExpiresIn is the longest access token lifetime in seconds considered sane for a client credentials grant
+const maxExpiresIn = 24 * 60 * 60
+
+type validateCredentialsGrantResponse struct {
+	client       *http.Client
+	clientCtxKey string
+	stepName     string
+}
+
+// NewValidateCredentialsGrantResponse makes a client credentials grant and validates the token response
+// as required by RFC6749 section 5.1: a Bearer token_type, a sane expires_in and a Cache-Control no-store header
+func NewValidateCredentialsGrantResponse(clientCtxKey string, httpClient *http.Client) Step {
+	return validateCredentialsGrantResponse{
+		client:       httpClient,
+		clientCtxKey: clientCtxKey,
+		stepName:     "Client credentials grant response is valid",
+	}
+}
+
+func (a validateCredentialsGrantResponse) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	softwareClient, err := ctx.GetClient(a.clientCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("getting software client object from context: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+	r, err := softwareClient.CredentialsGrantRequest("")
+	if err != nil {
+		msg := fmt.Sprintf("unable to build request object: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	r.Header.Set("Content-type", "application/x-www-form-urlencoded")
+	debug.Log(http2.DebugRequest(r))
+
+	response, err := a.client.Do(r)
+	if err != nil {
+		message := fmt.Sprintf("error making token request call: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+	defer response.Body.Close()
+	debug.LogInteractionID(response)
+	debug.Log(http2.DebugResponse(response))
+
+	if response.StatusCode != http.StatusOK {
+		message := fmt.Sprintf("unexpected status code %d, should be %d", response.StatusCode, http.StatusOK)
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+
+	var credentialsGrantResponse auth.CredentialsGrantResponse
+	if err = json.NewDecoder(response.Body).Decode(&credentialsGrantResponse); err != nil {
+		message := fmt.Sprintf("error decoding body content: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+
+	var failures []string
+	if !strings.EqualFold(credentialsGrantResponse.TokenType, "Bearer") {
+		failures = append(failures, fmt.Sprintf("token_type %q should be Bearer", credentialsGrantResponse.TokenType))
+	}
+	expiresIn := credentialsGrantResponse.ExpiresIn
+	if expiresIn <= 0 || expiresIn > maxExpiresIn {
+		failures = append(
+			failures,
+			fmt.Sprintf("expires_in %d should be present and between 1 and %d seconds", expiresIn, maxExpiresIn),
+		)
+	}
+	if !hasNoStore(response.Header.Values("Cache-Control")) {
+		failures = append(failures, "Cache-Control header should be no-store")
+	}
+
+	if len(failures) > 0 {
+		return NewFailResultWithDebug(a.stepName, strings.Join(failures, ", "), debug)
+	}
+
+	return NewPassResultWithDebug(a.stepName, debug)
+}
+
+func hasNoStore(cacheControl []string) bool {
+	for _, header := range cacheControl {
+		for _, directive := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package step
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func credentialsGrantResponseServer(t *testing.T, cacheControl, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", cacheControl)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+}
+
+func TestValidateCredentialsGrantResponse_Pass(t *testing.T) {
+	server := credentialsGrantResponseServer(
+		t,
+		"private, No-Store",
+		`{"access_token": "takeit", "token_type": "bearer", "expires_in": 3600}`,
+	)
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewValidateCredentialsGrantResponse("clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Client credentials grant response is valid", result.Name)
+}
+
+func TestValidateCredentialsGrantResponse_FailsOnInvalidResponse(t *testing.T) {
+	server := credentialsGrantResponseServer(t, "no-cache", `{"access_token": "takeit", "token_type": "mac"}`)
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewValidateCredentialsGrantResponse("clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		`token_type "mac" should be Bearer, expires_in 0 should be present and between 1 and 86400 seconds, `+
+			"Cache-Control header should be no-store",
+		result.FailReason,
+	)
+}
+
+func TestValidateCredentialsGrantResponse_FailsOnStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewValidateCredentialsGrantResponse("clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "unexpected status code 401, should be 200", result.FailReason)
+}
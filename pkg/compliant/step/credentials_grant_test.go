@@ -38,7 +38,14 @@ func TestClientCredentialsGrant(t *testing.T) {
 
 func TestClientCredentialsGrant_HandlesClientNotFound(t *testing.T) {
 	ctx := NewContext()
-	step := NewClientRetrieve("responseCtxKey", "localhost", "clientKey", "grantTokenKey", &http.Client{})
+	step := NewClientRetrieve(
+		"responseCtxKey",
+		"localhost",
+		"clientKey",
+		"grantTokenKey",
+		"registrationAccessKey",
+		&http.Client{},
+	)
 
 	result := step.Run(ctx)
 
@@ -49,3 +56,43 @@ func TestClientCredentialsGrant_HandlesClientNotFound(t *testing.T) {
 		result.FailReason,
 	)
 }
+
+func TestClientCredentialsGrantWithScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "accounts payments", r.PostForm.Get("scope"))
+		_, err := w.Write([]byte(`{"access_token": "takeit", "scope": "accounts"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	scopes := []string{"accounts", "payments"}
+	step := NewClientCredentialsGrantWithScope("clientGrantKey", "clientKey", server.URL, scopes, server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	token, err := ctx.GetGrantToken("clientGrantKey")
+	require.NoError(t, err)
+	assert.Equal(t, "accounts", token.Scope)
+}
+
+func TestClientCredentialsGrantWithScope_FailsOnScopeNotRequested(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"access_token": "takeit", "scope": "accounts payments fundsconfirmations"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	scopes := []string{"accounts"}
+	step := NewClientCredentialsGrantWithScope("clientGrantKey", "clientKey", server.URL, scopes, server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "granted scope payments fundsconfirmations was not requested", result.FailReason)
+}
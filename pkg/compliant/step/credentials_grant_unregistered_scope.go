@@ -0,0 +1,88 @@
+package step
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type clientCredentialsGrantUnregisteredScope struct {
+	client       *http.Client
+	clientCtxKey string
+	scope        string
+	stepName     string
+}
+
+// NewClientCredentialsGrantUnregisteredScope asserts a token endpoint doesn't grant a scope outside the registration.
+// The request should be rejected with invalid_scope, an AS may instead drop the scope from the grant (RFC6749 3.3)
+// which is a warning.
+func NewClientCredentialsGrantUnregisteredScope(clientCtxKey, scope string, httpClient *http.Client) Step {
+	return clientCredentialsGrantUnregisteredScope{
+		client:       httpClient,
+		clientCtxKey: clientCtxKey,
+		scope:        scope,
+		stepName:     "Client credentials grant rejects scope outside the registration",
+	}
+}
+
+func (a clientCredentialsGrantUnregisteredScope) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	softwareClient, err := ctx.GetClient(a.clientCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("getting software client object from context: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	r, err := softwareClient.CredentialsGrantRequest(a.scope)
+	if err != nil {
+		msg := fmt.Sprintf("unable to build request object: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	r.Header.Set("Content-type", "application/x-www-form-urlencoded")
+	debug.Log(http2.DebugRequest(r))
+
+	response, err := a.client.Do(r)
+	if err != nil {
+		message := fmt.Sprintf("error making token request call: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+	defer response.Body.Close()
+	debug.LogInteractionID(response)
+	debug.Log(http2.DebugResponse(response))
+
+	switch response.StatusCode {
+	case http.StatusBadRequest, http.StatusUnauthorized:
+		return NewPassResultWithDebug(a.stepName, debug)
+	case http.StatusOK:
+	default:
+		message := fmt.Sprintf(
+			"unexpected status code %d, should be %d or %d",
+			response.StatusCode,
+			http.StatusBadRequest,
+			http.StatusUnauthorized,
+		)
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+
+	var credentialsGrantResponse auth.CredentialsGrantResponse
+	if err = json.NewDecoder(response.Body).Decode(&credentialsGrantResponse); err != nil {
+		message := fmt.Sprintf("error decoding body content: %s", err.Error())
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+
+	// an omitted scope in the response means the requested scope was granted
+	granted := credentialsGrantResponse.Scope
+	if granted == "" || containsValue(strings.Fields(granted), a.scope) {
+		message := fmt.Sprintf("token granted scope %s outside the registration", a.scope)
+		return NewFailResultWithDebug(a.stepName, message, debug)
+	}
+
+	warning := fmt.Sprintf("scope %s was dropped from the grant instead of rejected with invalid_scope", a.scope)
+	return NewPassResultWithWarnings(a.stepName, []string{warning}, debug)
+}
@@ -0,0 +1,57 @@
+package step
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unregisteredScopeResult(t *testing.T, handler http.HandlerFunc) Result {
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewClientCredentialsGrantUnregisteredScope("clientKey", "payments", server.Client())
+
+	return step.Run(ctx)
+}
+
+func TestClientCredentialsGrantUnregisteredScope_PassesWhenRejected(t *testing.T) {
+	result := unregisteredScopeResult(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "payments", r.PostForm.Get("scope"))
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Client credentials grant rejects scope outside the registration", result.Name)
+}
+
+func TestClientCredentialsGrantUnregisteredScope_WarnsWhenScopeDropped(t *testing.T) {
+	result := unregisteredScopeResult(t, func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"access_token": "takeit", "scope": "accounts"}`))
+		assert.NoError(t, err)
+	})
+
+	assert.True(t, result.Pass)
+	assert.Equal(
+		t,
+		[]string{"scope payments was dropped from the grant instead of rejected with invalid_scope"},
+		result.Warnings,
+	)
+}
+
+func TestClientCredentialsGrantUnregisteredScope_FailsWhenGranted(t *testing.T) {
+	result := unregisteredScopeResult(t, func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"access_token": "takeit"}`))
+		assert.NoError(t, err)
+	})
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "token granted scope payments outside the registration", result.FailReason)
+}
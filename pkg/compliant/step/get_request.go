@@ -14,10 +14,15 @@ type getRequest struct {
 }
 
 func NewGetRequest(url, responseContextVar string, httpClient *http.Client) Step {
+	return NewNamedGetRequest(fmt.Sprintf("GET request %s", url), url, responseContextVar, httpClient)
+}
+
+// NewNamedGetRequest same as NewGetRequest with a step name that doesn't depend on the url
+func NewNamedGetRequest(stepName, url, responseContextVar string, httpClient *http.Client) Step {
 	return getRequest{
 		url:            url,
 		responseCtxKey: responseContextVar,
-		stepName:       fmt.Sprintf("GET request %s", url),
+		stepName:       stepName,
 		httpClient:     httpClient,
 	}
 }
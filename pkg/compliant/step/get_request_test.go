@@ -28,6 +28,17 @@ func TestGetRequest_Pass(t *testing.T) {
 	assert.Equal(t, "", result.FailReason)
 }
 
+func TestNamedGetRequest_Pass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+
+	result := NewNamedGetRequest("GET registration endpoint", server.URL, "response", server.Client()).
+		Run(NewContext())
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "GET registration endpoint", result.Name)
+}
+
 func TestGetRequest_FailsIfHttpCallFails(t *testing.T) {
 	ctx := NewContext()
 	step := NewGetRequest("invalid_url", "response", &http.Client{})
@@ -0,0 +1,49 @@
+package step
+
+import (
+	"fmt"
+
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type assertInteractionIDEchoed struct {
+	responseCtxKey string
+	stepName       string
+}
+
+// NewAssertInteractionIDEchoed asserts the ASPSP echoes back in the response
+// the x-fapi-interaction-id sent by the secure client with the request
+func NewAssertInteractionIDEchoed(responseCtxKey string) Step {
+	return assertInteractionIDEchoed{
+		responseCtxKey: responseCtxKey,
+		stepName:       fmt.Sprintf("Assert %s is echoed", http2.InteractionIDHeader),
+	}
+}
+
+func (a assertInteractionIDEchoed) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	r, err := ctx.GetResponse(a.responseCtxKey)
+	if err != nil {
+		return NewFailResult(a.stepName, fmt.Sprintf("getting response object from context: %s", err.Error()))
+	}
+
+	debug.LogInteractionID(r)
+	sent := http2.InteractionID(r)
+	if sent == "" {
+		msg := fmt.Sprintf("no %s was sent with the request", http2.InteractionIDHeader)
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	received := r.Header.Get(http2.InteractionIDHeader)
+	if received == "" {
+		msg := fmt.Sprintf("response doesn't echo %s %s", http2.InteractionIDHeader, sent)
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+	if received != sent {
+		msg := fmt.Sprintf("response %s %s doesn't match the request %s", http2.InteractionIDHeader, received, sent)
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+
+	return NewPassResultWithDebug(a.stepName, debug)
+}
@@ -0,0 +1,72 @@
+package step
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func interactionResponse(sent, received string) *http.Response {
+	request := &http.Request{Header: http.Header{}}
+	if sent != "" {
+		request.Header.Set("x-fapi-interaction-id", sent)
+	}
+	response := &http.Response{Header: http.Header{}, Request: request}
+	if received != "" {
+		response.Header.Set("x-fapi-interaction-id", received)
+	}
+	return response
+}
+
+func TestAssertInteractionIDEchoed_Pass(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetResponse("response", interactionResponse("interaction-id", "interaction-id"))
+
+	result := NewAssertInteractionIDEchoed("response").Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Assert x-fapi-interaction-id is echoed", result.Name)
+	assert.Equal(t, []string{"interaction-id"}, result.Debug.InteractionIDs)
+}
+
+func TestAssertInteractionIDEchoed_FailsIfNotEchoed(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetResponse("response", interactionResponse("interaction-id", ""))
+
+	result := NewAssertInteractionIDEchoed("response").Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "response doesn't echo x-fapi-interaction-id interaction-id", result.FailReason)
+}
+
+func TestAssertInteractionIDEchoed_FailsIfDifferent(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetResponse("response", interactionResponse("interaction-id", "other-id"))
+
+	result := NewAssertInteractionIDEchoed("response").Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"response x-fapi-interaction-id other-id doesn't match the request interaction-id",
+		result.FailReason,
+	)
+}
+
+func TestAssertInteractionIDEchoed_FailsIfNotSent(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetResponse("response", interactionResponse("", ""))
+
+	result := NewAssertInteractionIDEchoed("response").Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "no x-fapi-interaction-id was sent with the request", result.FailReason)
+}
+
+func TestAssertInteractionIDEchoed_FailsIfResponseNotInContext(t *testing.T) {
+	result := NewAssertInteractionIDEchoed("response").Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "getting response object from context: key not found in context", result.FailReason)
+}
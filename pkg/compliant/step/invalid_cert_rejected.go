@@ -0,0 +1,130 @@
+package step
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type invalidCertRegisterRejected struct {
+	stepName             string
+	registrationEndpoint string
+	jwtClaimsCtxKey      string
+	certDescription      string
+	client               *http.Client
+}
+
+// NewInvalidCertRegisterRejected asserts a registration request sent with an invalid transport certificate,
+// ex: `with an expired certificate`, is refused with a TLS alert or with a 401 or 403 status code
+func NewInvalidCertRegisterRejected(
+	registrationEndpoint, jwtClaimsCtxKey, certDescription string,
+	httpClient *http.Client,
+) Step {
+	return invalidCertRegisterRejected{
+		stepName:             fmt.Sprintf("Software client register rejected %s", certDescription),
+		registrationEndpoint: registrationEndpoint,
+		jwtClaimsCtxKey:      jwtClaimsCtxKey,
+		certDescription:      certDescription,
+		client:               httpClient,
+	}
+}
+
+func (s invalidCertRegisterRejected) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	jwtClaims, err := ctx.GetString(s.jwtClaimsCtxKey)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("getting jwt claims: %s", err.Error()), debug)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.registrationEndpoint, bytes.NewBufferString(jwtClaims))
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("creating jose post request: %s", err.Error()), debug)
+	}
+	req.Header.Add("Content-Type", "application/jose")
+	req.Header.Add("Accept", "application/json")
+	debug.Log(http2.DebugRequest(req))
+
+	return invalidCertResult(s.stepName, s.certDescription, s.client, req, debug)
+}
+
+type invalidCertCredentialsGrantRejected struct {
+	stepName        string
+	clientCtxKey    string
+	certDescription string
+	client          *http.Client
+}
+
+// NewInvalidCertCredentialsGrantRejected asserts a client credentials grant request of the registered client
+// sent with an invalid transport certificate is refused with a TLS alert or with a 401 or 403 status code
+func NewInvalidCertCredentialsGrantRejected(clientCtxKey, certDescription string, httpClient *http.Client) Step {
+	return invalidCertCredentialsGrantRejected{
+		stepName:        fmt.Sprintf("Client credentials grant rejected %s", certDescription),
+		clientCtxKey:    clientCtxKey,
+		certDescription: certDescription,
+		client:          httpClient,
+	}
+}
+
+func (s invalidCertCredentialsGrantRejected) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	softwareClient, err := ctx.GetClient(s.clientCtxKey)
+	if err != nil {
+		msg := fmt.Sprintf("getting software client object from context: %s", err.Error())
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+
+	req, err := softwareClient.CredentialsGrantRequest("")
+	if err != nil {
+		msg := fmt.Sprintf("unable to build request object: %s", err.Error())
+		return NewFailResultWithDebug(s.stepName, msg, debug)
+	}
+	req.Header.Set("Content-type", "application/x-www-form-urlencoded")
+	debug.Log(http2.DebugRequest(req))
+
+	return invalidCertResult(s.stepName, s.certDescription, s.client, req, debug)
+}
+
+// invalidCertResult makes the request with the invalid certificate client,
+// passing when the server refuses the connection with a TLS alert or the response status code is 401 or 403,
+// network failures like DNS resolution or timeouts and the probe failing to verify the server certificate
+// fail as the invalid certificate wasn't exercised
+func invalidCertResult(
+	stepName, certDescription string,
+	client *http.Client,
+	req *http.Request,
+	debug *DebugMessages,
+) Result {
+	response, err := client.Do(req)
+	if err != nil {
+		if tlsAlert(err) {
+			debug.Logf("connection %s refused: %v", certDescription, err)
+			return NewPassResultWithDebug(stepName, debug)
+		}
+		if serverVerificationError(err) {
+			msg := fmt.Sprintf("probe could not reach the server, verifying its certificate failed: %v", err)
+			return NewFailResultWithDebug(stepName, msg, debug)
+		}
+		msg := fmt.Sprintf("unable to connect %s, not a TLS rejection: %v", certDescription, err)
+		return NewFailResultWithDebug(stepName, msg, debug)
+	}
+	defer response.Body.Close()
+	debug.LogInteractionID(response)
+	debug.Log(http2.DebugResponse(response))
+
+	if response.StatusCode != http.StatusUnauthorized && response.StatusCode != http.StatusForbidden {
+		msg := fmt.Sprintf(
+			"unexpected status code %d %s, should be a TLS failure, %d or %d",
+			response.StatusCode,
+			certDescription,
+			http.StatusUnauthorized,
+			http.StatusForbidden,
+		)
+		return NewFailResultWithDebug(stepName, msg, debug)
+	}
+
+	return NewPassResultWithDebug(stepName, debug)
+}
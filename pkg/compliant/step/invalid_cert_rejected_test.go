@@ -0,0 +1,132 @@
+package step
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidCertRegisterRejected_PassesWhenConnectionRefused(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetString("jwtClaims", "jwt")
+	step := NewInvalidCertRegisterRejected(server.URL, "jwtClaims", "without client certificate", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Software client register rejected without client certificate", result.Name)
+}
+
+func TestInvalidCertRegisterRejected_FailsOnNetworkError(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetString("jwtClaims", "jwt")
+	step := NewInvalidCertRegisterRejected(
+		"https://127.0.0.1:0",
+		"jwtClaims",
+		"with an expired certificate",
+		&http.Client{},
+	)
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "unable to connect with an expired certificate, not a TLS rejection")
+}
+
+func TestInvalidCertRegisterRejected_FailsIfServerCertificateNotVerified(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetString("jwtClaims", "jwt")
+	step := NewInvalidCertRegisterRejected(server.URL, "jwtClaims", "with an expired certificate", &http.Client{})
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "probe could not reach the server, verifying its certificate failed")
+}
+
+func TestInvalidCertRegisterRejected_PassesWhenForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/jose", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetString("jwtClaims", "jwt")
+	step := NewInvalidCertRegisterRejected(server.URL, "jwtClaims", "with an expired certificate", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+}
+
+func TestInvalidCertRegisterRejected_FailsWhenRegistered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetString("jwtClaims", "jwt")
+	step := NewInvalidCertRegisterRejected(server.URL, "jwtClaims", "with an expired certificate", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"unexpected status code 201 with an expired certificate, should be a TLS failure, 401 or 403",
+		result.FailReason,
+	)
+}
+
+func TestInvalidCertRegisterRejected_FailsWithoutJwtClaims(t *testing.T) {
+	step := NewInvalidCertRegisterRejected("https://as.example.com", "jwtClaims", "", &http.Client{})
+
+	result := step.Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "getting jwt claims: key not found in context", result.FailReason)
+}
+
+func TestInvalidCertCredentialsGrantRejected_PassesWhenUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewInvalidCertCredentialsGrantRejected("clientKey", "with a certificate from an untrusted CA", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Client credentials grant rejected with a certificate from an untrusted CA", result.Name)
+}
+
+func TestInvalidCertCredentialsGrantRejected_FailsWhenGranted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, clientSecret, server.URL))
+	step := NewInvalidCertCredentialsGrantRejected("clientKey", "without client certificate", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"unexpected status code 200 without client certificate, should be a TLS failure, 401 or 403",
+		result.FailReason,
+	)
+}
@@ -0,0 +1,150 @@
+package step
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+)
+
+const wellknownPath = "/.well-known/openid-configuration"
+
+type openIDConfigValidate struct {
+	stepName          string
+	config            openid.Configuration
+	wellknownEndpoint string
+}
+
+// NewValidateOpenIDConfig checks the discovery document has the OIDC Discovery required metadata,
+// uses https for all endpoints, advertises FAPI certificate bound access tokens and the openid scope,
+// and that the issuer is identical to the well-known endpoint prefix the document was retrieved from
+func NewValidateOpenIDConfig(config openid.Configuration, wellknownEndpoint string) Step {
+	return openIDConfigValidate{
+		stepName:          "Validate OpenID configuration",
+		config:            config,
+		wellknownEndpoint: wellknownEndpoint,
+	}
+}
+
+func (v openIDConfigValidate) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	var failures []string
+	failures = append(failures, v.requiredFields()...)
+	failures = append(failures, v.httpsEndpoints()...)
+	failures = append(failures, v.issuerMatchesWellknown()...)
+	failures = append(failures, v.scopesSupported()...)
+
+	// FAPI RW: access tokens must be sender constrained with MTLS
+	boundTokens := v.config.TLSClientCertificateBoundAccessTokens
+	if boundTokens == nil || !*boundTokens {
+		failures = append(failures, "tls_client_certificate_bound_access_tokens must be true")
+	}
+
+	if len(failures) > 0 {
+		for _, failure := range failures {
+			debug.Log(failure)
+		}
+		return NewFailResultWithDebug(v.stepName, strings.Join(failures, ", "), debug)
+	}
+
+	return NewPassResultWithDebug(v.stepName, debug)
+}
+
+type metadataField struct {
+	name  string
+	value string
+}
+
+func (v openIDConfigValidate) requiredFields() []string {
+	required := []struct {
+		name    string
+		present bool
+	}{
+		{"issuer", v.config.Issuer != ""},
+		{"authorization_endpoint", v.config.AuthorizationEndpoint != ""},
+		{"token_endpoint", v.config.TokenEndpoint != ""},
+		{"jwks_uri", v.config.JwksURI != ""},
+		{"registration_endpoint", v.config.RegistrationEndpoint != nil},
+		{"response_types_supported", v.config.ResponseTypesSupported != nil},
+		{"subject_types_supported", len(v.config.SubjectTypesSupported) > 0},
+		{"id_token_signing_alg_values_supported", len(v.config.IdTokenSigningAlgSupported) > 0},
+		{"token_endpoint_auth_methods_supported", len(v.config.TokenEndpointAuthMethodsSupported) > 0},
+		{"scopes_supported", len(v.config.ScopesSupported) > 0},
+	}
+
+	var failures []string
+	for _, field := range required {
+		if !field.present {
+			failures = append(failures, fmt.Sprintf("%s is missing", field.name))
+		}
+	}
+	return failures
+}
+
+func (v openIDConfigValidate) httpsEndpoints() []string {
+	endpoints := []metadataField{
+		{"issuer", v.config.Issuer},
+		{"authorization_endpoint", v.config.AuthorizationEndpoint},
+		{"token_endpoint", v.config.TokenEndpoint},
+		{"jwks_uri", v.config.JwksURI},
+		{"registration_endpoint", v.config.RegistrationEndpointAsString()},
+		{"userinfo_endpoint", v.config.UserinfoEndpoint},
+		{"introspection_endpoint", v.config.IntrospectionEndpoint},
+	}
+	aliases := make([]string, 0, len(v.config.MTLSEndpointAliases))
+	for name := range v.config.MTLSEndpointAliases {
+		aliases = append(aliases, name)
+	}
+	sort.Strings(aliases)
+	for _, name := range aliases {
+		endpoints = append(endpoints, metadataField{"mtls_endpoint_aliases." + name, v.config.MTLSEndpointAliases[name]})
+	}
+
+	var failures []string
+	for _, endpoint := range endpoints {
+		if endpoint.value == "" {
+			continue
+		}
+		if !isHttpsURL(endpoint.value) {
+			failures = append(failures, fmt.Sprintf("%s %s is not a valid https URL", endpoint.name, endpoint.value))
+		}
+	}
+	return failures
+}
+
+// OIDC Discovery: the issuer value returned MUST be identical to the Issuer URL
+// that was used as the prefix to /.well-known/openid-configuration
+func (v openIDConfigValidate) issuerMatchesWellknown() []string {
+	if v.config.Issuer == "" {
+		return nil
+	}
+	if v.config.Issuer+wellknownPath != v.wellknownEndpoint {
+		msg := fmt.Sprintf("issuer %s does not match well-known endpoint %s", v.config.Issuer, v.wellknownEndpoint)
+		return []string{msg}
+	}
+	return nil
+}
+
+// OIDC Discovery: the openid scope value MUST be listed in scopes_supported
+func (v openIDConfigValidate) scopesSupported() []string {
+	if len(v.config.ScopesSupported) == 0 {
+		return nil
+	}
+	for _, scope := range v.config.ScopesSupported {
+		if scope == "openid" {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("scopes_supported %v does not include openid", v.config.ScopesSupported)}
+}
+
+func isHttpsURL(value string) bool {
+	u, err := url.ParseRequestURI(value)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && u.Host != ""
+}
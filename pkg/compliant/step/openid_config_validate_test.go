@@ -0,0 +1,108 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/stretchr/testify/assert"
+)
+
+func validOpenIDConfig() openid.Configuration {
+	registrationEndpoint := "https://as.example.com/register"
+	responseTypes := []string{"code id_token"}
+	boundTokens := true
+	return openid.Configuration{
+		Issuer:                                "https://as.example.com",
+		AuthorizationEndpoint:                 "https://as.example.com/authorize",
+		RegistrationEndpoint:                  &registrationEndpoint,
+		TokenEndpoint:                         "https://as.example.com/token",
+		JwksURI:                               "https://as.example.com/jwks",
+		SubjectTypesSupported:                 []string{"public"},
+		IdTokenSigningAlgSupported:            []string{"PS256"},
+		TokenEndpointAuthMethodsSupported:     []string{"private_key_jwt"},
+		ScopesSupported:                       []string{"openid", "accounts"},
+		ResponseTypesSupported:                &responseTypes,
+		TLSClientCertificateBoundAccessTokens: &boundTokens,
+		MTLSEndpointAliases:                   map[string]string{"token_endpoint": "https://mtls.as.example.com/token"},
+	}
+}
+
+func TestNewValidateOpenIDConfig_Pass(t *testing.T) {
+	step := NewValidateOpenIDConfig(validOpenIDConfig(), "https://as.example.com/.well-known/openid-configuration")
+
+	result := step.Run(NewContext())
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Validate OpenID configuration", result.Name)
+}
+
+func TestNewValidateOpenIDConfig_FailsOnMissingRequiredFields(t *testing.T) {
+	step := NewValidateOpenIDConfig(openid.Configuration{}, "https://as.example.com/.well-known/openid-configuration")
+
+	result := step.Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "issuer is missing")
+	assert.Contains(t, result.FailReason, "jwks_uri is missing")
+	assert.Contains(t, result.FailReason, "id_token_signing_alg_values_supported is missing")
+	assert.Contains(t, result.FailReason, "scopes_supported is missing")
+	assert.Contains(t, result.FailReason, "tls_client_certificate_bound_access_tokens must be true")
+}
+
+func TestNewValidateOpenIDConfig_FailsOnNonHttpsEndpoint(t *testing.T) {
+	config := validOpenIDConfig()
+	config.TokenEndpoint = "http://as.example.com/token"
+	config.MTLSEndpointAliases["token_endpoint"] = "mtls.as.example.com/token"
+	step := NewValidateOpenIDConfig(config, "https://as.example.com/.well-known/openid-configuration")
+
+	result := step.Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"token_endpoint http://as.example.com/token is not a valid https URL, "+
+			"mtls_endpoint_aliases.token_endpoint mtls.as.example.com/token is not a valid https URL",
+		result.FailReason,
+	)
+}
+
+func TestNewValidateOpenIDConfig_FailsOnIssuerNotMatchingWellknown(t *testing.T) {
+	step := NewValidateOpenIDConfig(validOpenIDConfig(), "https://other.example.com/.well-known/openid-configuration")
+
+	result := step.Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"issuer https://as.example.com does not match well-known endpoint "+
+			"https://other.example.com/.well-known/openid-configuration",
+		result.FailReason,
+	)
+}
+
+func TestNewValidateOpenIDConfig_FailsOnIssuerTrailingSlash(t *testing.T) {
+	config := validOpenIDConfig()
+	config.Issuer = "https://as.example.com/"
+	step := NewValidateOpenIDConfig(config, "https://as.example.com/.well-known/openid-configuration")
+
+	result := step.Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"issuer https://as.example.com/ does not match well-known endpoint "+
+			"https://as.example.com/.well-known/openid-configuration",
+		result.FailReason,
+	)
+}
+
+func TestNewValidateOpenIDConfig_FailsOnScopesSupportedWithoutOpenid(t *testing.T) {
+	config := validOpenIDConfig()
+	config.ScopesSupported = []string{"accounts"}
+	step := NewValidateOpenIDConfig(config, "https://as.example.com/.well-known/openid-configuration")
+
+	result := step.Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "scopes_supported [accounts] does not include openid", result.FailReason)
+}
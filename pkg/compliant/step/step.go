@@ -2,7 +2,11 @@ package step
 
 import (
 	"fmt"
+	"net/http"
 	"time"
+
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/redact"
 )
 
 type Step interface {
@@ -13,6 +17,7 @@ type Result struct {
 	Name       string
 	Pass       bool
 	FailReason string
+	Warnings   []string
 	Debug      DebugMessages
 }
 
@@ -34,6 +39,8 @@ type DebugMessage struct {
 
 type DebugMessages struct {
 	Item []DebugMessage
+	// InteractionIDs of the requests made by a step, ASPSP support teams use them to find the requests in their logs
+	InteractionIDs []string
 }
 
 func NewDebug() *DebugMessages {
@@ -54,6 +61,16 @@ func (d *DebugMessages) Logf(format string, a ...interface{}) {
 	})
 }
 
+// LogInteractionID records the x-fapi-interaction-id sent with the request of a response
+func (d *DebugMessages) LogInteractionID(r *http.Response) {
+	interactionID := http2.InteractionID(r)
+	if interactionID == "" {
+		return
+	}
+	d.InteractionIDs = append(d.InteractionIDs, interactionID)
+	d.Logf("%s: %s", http2.InteractionIDHeader, interactionID)
+}
+
 func NewPassResult(name string) Result {
 	return Result{Name: name, Pass: true}
 }
@@ -62,6 +79,12 @@ func NewPassResultWithDebug(name string, log *DebugMessages) Result {
 	return Result{Name: name, Pass: true, Debug: *log}
 }
 
+// NewPassResultWithWarnings is a pass result that carries non blocking findings,
+// ex: metadata an ASPSP is allowed to modify but that the TPP should be told about
+func NewPassResultWithWarnings(name string, warnings []string, log *DebugMessages) Result {
+	return Result{Name: name, Pass: true, Warnings: warnings, Debug: *log}
+}
+
 func NewFailResult(name, reason string) Result {
 	return Result{Name: name, Pass: false, FailReason: reason}
 }
@@ -69,3 +92,25 @@ func NewFailResult(name, reason string) Result {
 func NewFailResultWithDebug(name, reason string, log *DebugMessages) Result {
 	return Result{Name: name, Pass: false, FailReason: reason, Debug: *log}
 }
+
+// Redacted is a copy of the debug messages with secrets masked, ie: authorization headers,
+// client secrets, access tokens and JWT signatures
+func (d DebugMessages) Redacted() DebugMessages {
+	redacted := DebugMessages{InteractionIDs: d.InteractionIDs}
+	for _, item := range d.Item {
+		redacted.Item = append(redacted.Item, DebugMessage{Time: item.Time, Message: redact.String(item.Message)})
+	}
+	return redacted
+}
+
+// Redacted is a copy of the result with secrets masked in its fail reason, warnings and debug messages
+func (r Result) Redacted() Result {
+	redacted := r
+	redacted.FailReason = redact.String(r.FailReason)
+	redacted.Warnings = nil
+	for _, warning := range r.Warnings {
+		redacted.Warnings = append(redacted.Warnings, redact.String(warning))
+	}
+	redacted.Debug = r.Debug.Redacted()
+	return redacted
+}
@@ -14,6 +14,15 @@ func TestNewPassResult(t *testing.T) {
 	assert.Empty(t, passingStep.FailReason)
 }
 
+func TestNewPassResultWithWarnings(t *testing.T) {
+	passingStep := NewPassResultWithWarnings("some step", []string{"heads up"}, NewDebug())
+
+	assert.True(t, passingStep.Pass)
+	assert.Equal(t, "some step", passingStep.Name)
+	assert.Empty(t, passingStep.FailReason)
+	assert.Equal(t, []string{"heads up"}, passingStep.Warnings)
+}
+
 func TestNewFailResult(t *testing.T) {
 	failingTest := NewFailResult("some other step", "computer says no")
 
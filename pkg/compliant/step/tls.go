@@ -0,0 +1,166 @@
+package step
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type assertFAPITLS struct {
+	responseCtxKey string
+	stepName       string
+}
+
+// NewAssertFAPITLS asserts the connection of a response negotiated TLS 1.3,
+// or TLS 1.2 with a cipher suite permitted by FAPI
+func NewAssertFAPITLS(responseCtxKey string) Step {
+	return assertFAPITLS{
+		responseCtxKey: responseCtxKey,
+		stepName:       "Assert TLS version and cipher suite are FAPI permitted",
+	}
+}
+
+func (a assertFAPITLS) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	r, err := ctx.GetResponse(a.responseCtxKey)
+	if err != nil {
+		return NewFailResult(a.stepName, fmt.Sprintf("getting response object from context: %s", err.Error()))
+	}
+	if r.TLS == nil {
+		return NewFailResultWithDebug(a.stepName, "response wasn't received over TLS", debug)
+	}
+
+	connection := http2.NewTLSConnection(r.TLS)
+	debug.Logf("tls: %s", connection)
+
+	switch r.TLS.Version {
+	case tls.VersionTLS13:
+		return NewPassResultWithDebug(a.stepName, debug)
+	case tls.VersionTLS12:
+		if !http2.FAPICipherSuite(r.TLS.CipherSuite) {
+			msg := fmt.Sprintf("cipher suite %s is not permitted with TLS 1.2", connection.CipherSuite)
+			return NewFailResultWithDebug(a.stepName, msg, debug)
+		}
+		return NewPassResultWithDebug(a.stepName, debug)
+	}
+
+	msg := fmt.Sprintf("TLS version %s is not permitted, should be TLS 1.2 or TLS 1.3", connection.Version)
+	return NewFailResultWithDebug(a.stepName, msg, debug)
+}
+
+type assertServerCertNotExpiring struct {
+	responseCtxKey string
+	days           int
+	stepName       string
+}
+
+// NewAssertServerCertNotExpiring asserts none of the certificates presented by the server
+// in the connection of a response expire within a number of days
+func NewAssertServerCertNotExpiring(responseCtxKey string, days int) Step {
+	return assertServerCertNotExpiring{
+		responseCtxKey: responseCtxKey,
+		days:           days,
+		stepName:       fmt.Sprintf("Assert server certificate doesn't expire within %d days", days),
+	}
+}
+
+func (a assertServerCertNotExpiring) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	r, err := ctx.GetResponse(a.responseCtxKey)
+	if err != nil {
+		return NewFailResult(a.stepName, fmt.Sprintf("getting response object from context: %s", err.Error()))
+	}
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return NewFailResultWithDebug(a.stepName, "response wasn't received over TLS", debug)
+	}
+
+	deadline := time.Now().AddDate(0, 0, a.days)
+	for _, cert := range http2.NewTLSConnection(r.TLS).Certificates {
+		debug.Logf("certificate %s expires %s", cert.Subject, cert.NotAfter.Format(time.RFC3339))
+		if cert.NotAfter.Before(deadline) {
+			msg := fmt.Sprintf(
+				"server certificate %s expires %s, within %d days",
+				cert.Subject,
+				cert.NotAfter.Format(time.RFC3339),
+				a.days,
+			)
+			return NewFailResultWithDebug(a.stepName, msg, debug)
+		}
+	}
+
+	return NewPassResultWithDebug(a.stepName, debug)
+}
+
+type assertHandshakeRejected struct {
+	stepName string
+	url      string
+	reason   string
+	client   *http.Client
+}
+
+// NewAssertHandshakeRejected asserts the server refuses the TLS handshake of a client
+// with a configuration it must not accept, ex: without a client certificate.
+// Only a TLS alert sent by the server is a rejection, network failures like DNS resolution or timeouts
+// and the probe failing to verify the server certificate fail the step
+func NewAssertHandshakeRejected(stepName, url, reason string, client *http.Client) Step {
+	return assertHandshakeRejected{
+		stepName: stepName,
+		url:      url,
+		reason:   reason,
+		client:   client,
+	}
+}
+
+func (a assertHandshakeRejected) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	debug.Logf("making get request %s: %s", a.reason, a.url)
+	r, err := a.client.Get(a.url)
+	if err != nil {
+		if tlsAlert(err) {
+			debug.Logf("connection refused: %v", err)
+			return NewPassResultWithDebug(a.stepName, debug)
+		}
+		if serverVerificationError(err) {
+			msg := fmt.Sprintf("probe could not reach the server, verifying its certificate failed: %v", err)
+			return NewFailResultWithDebug(a.stepName, msg, debug)
+		}
+		msg := fmt.Sprintf("unable to connect %s, not a TLS rejection: %v", a.reason, err)
+		return NewFailResultWithDebug(a.stepName, msg, debug)
+	}
+	defer r.Body.Close()
+	debug.LogInteractionID(r)
+	debug.Log(http2.DebugResponse(r))
+
+	msg := fmt.Sprintf("server accepted a connection %s, status code %d", a.reason, r.StatusCode)
+	return NewFailResultWithDebug(a.stepName, msg, debug)
+}
+
+// tlsAlert checks if a request was refused by the server with a TLS alert, as opposed to network failures
+// like DNS resolution, timeouts or proxy errors, a server not speaking TLS or the probe failing to verify the server
+func tlsAlert(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "remote error: tls:")
+}
+
+// serverVerificationError checks if a request failed because the probe couldn't verify the server certificate,
+// the connection was dropped by the client so the server didn't get to accept or refuse it
+func serverVerificationError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCert x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &invalidCert) || errors.As(err, &hostname) {
+		return true
+	}
+	return !tlsAlert(err) && strings.Contains(err.Error(), "x509: ")
+}
@@ -0,0 +1,194 @@
+package step
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tlsResponse(version, cipherSuite uint16, notAfter time.Time) *http.Response {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "as.example.com"}, NotAfter: notAfter}
+	return &http.Response{
+		TLS: &tls.ConnectionState{
+			Version:          version,
+			CipherSuite:      cipherSuite,
+			PeerCertificates: []*x509.Certificate{cert},
+		},
+	}
+}
+
+func TestAssertFAPITLS_PassesWithTLS13(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetResponse("response", tlsResponse(tls.VersionTLS13, tls.TLS_AES_128_GCM_SHA256, time.Now()))
+
+	result := NewAssertFAPITLS("response").Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Assert TLS version and cipher suite are FAPI permitted", result.Name)
+}
+
+func TestAssertFAPITLS_PassesWithTLS12PermittedCipherSuite(t *testing.T) {
+	ctx := NewContext()
+	response := tlsResponse(tls.VersionTLS12, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, time.Now())
+	ctx.SetResponse("response", response)
+
+	result := NewAssertFAPITLS("response").Run(ctx)
+
+	assert.True(t, result.Pass)
+}
+
+func TestAssertFAPITLS_FailsWithTLS12NonPermittedCipherSuite(t *testing.T) {
+	ctx := NewContext()
+	response := tlsResponse(tls.VersionTLS12, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256, time.Now())
+	ctx.SetResponse("response", response)
+
+	result := NewAssertFAPITLS("response").Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"cipher suite TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256 is not permitted with TLS 1.2",
+		result.FailReason,
+	)
+}
+
+func TestAssertFAPITLS_FailsWithTLS11(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetResponse("response", tlsResponse(tls.VersionTLS11, tls.TLS_RSA_WITH_AES_128_CBC_SHA, time.Now()))
+
+	result := NewAssertFAPITLS("response").Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "TLS version TLS 1.1 is not permitted, should be TLS 1.2 or TLS 1.3", result.FailReason)
+}
+
+func TestAssertFAPITLS_FailsWithoutTLS(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetResponse("response", &http.Response{})
+
+	result := NewAssertFAPITLS("response").Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "response wasn't received over TLS", result.FailReason)
+}
+
+func TestAssertServerCertNotExpiring_Pass(t *testing.T) {
+	ctx := NewContext()
+	notAfter := time.Now().AddDate(0, 0, 31)
+	ctx.SetResponse("response", tlsResponse(tls.VersionTLS13, tls.TLS_AES_128_GCM_SHA256, notAfter))
+
+	result := NewAssertServerCertNotExpiring("response", 30).Run(ctx)
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Assert server certificate doesn't expire within 30 days", result.Name)
+}
+
+func TestAssertServerCertNotExpiring_FailsIfExpiring(t *testing.T) {
+	ctx := NewContext()
+	notAfter := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx.SetResponse("response", tlsResponse(tls.VersionTLS13, tls.TLS_AES_128_GCM_SHA256, notAfter))
+
+	result := NewAssertServerCertNotExpiring("response", 30).Run(ctx)
+
+	assert.False(t, result.Pass)
+	assert.Equal(
+		t,
+		"server certificate CN=as.example.com expires 2020-01-01T00:00:00Z, within 30 days",
+		result.FailReason,
+	)
+}
+
+func TestAssertHandshakeRejected_PassesIfConnectionRefused(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	result := NewAssertHandshakeRejected("step", server.URL, "without client certificate", server.Client()).
+		Run(NewContext())
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "step", result.Name)
+}
+
+func TestAssertHandshakeRejected_FailsIfConnectionAccepted(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result := NewAssertHandshakeRejected("step", server.URL, "without client certificate", server.Client()).
+		Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "server accepted a connection without client certificate, status code 200", result.FailReason)
+}
+
+func TestAssertHandshakeRejected_FailsOnNetworkError(t *testing.T) {
+	result := NewAssertHandshakeRejected("step", "https://127.0.0.1:0", "without client certificate", &http.Client{}).
+		Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "unable to connect without client certificate, not a TLS rejection")
+}
+
+func TestAssertHandshakeRejected_FailsIfServerCertificateNotVerified(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result := NewAssertHandshakeRejected("step", server.URL, "without client certificate", &http.Client{}).
+		Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "probe could not reach the server, verifying its certificate failed")
+}
+
+func TestAssertHandshakeRejected_FailsIfServerDoesNotSpeakTLS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	endpoint := "https://" + server.Listener.Addr().String()
+
+	result := NewAssertHandshakeRejected("step", endpoint, "without client certificate", &http.Client{}).
+		Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.FailReason, "unable to connect without client certificate, not a TLS rejection")
+}
+
+func TestTLSAlert(t *testing.T) {
+	unknownAuthority := &url.Error{Op: "Get", URL: "https://as", Err: x509.UnknownAuthorityError{}}
+	alert := &url.Error{Op: "Get", URL: "https://as", Err: errors.New("remote error: tls: bad certificate")}
+	plainHTTP := &url.Error{
+		Op:  "Get",
+		URL: "https://as",
+		Err: errors.New("tls: first record does not look like a TLS handshake"),
+	}
+	dial := &url.Error{Op: "Get", URL: "https://as", Err: errors.New("dial tcp: lookup as: no such host")}
+	timeout := &url.Error{Op: "Get", URL: "https://as", Err: errors.New("net/http: TLS handshake timeout")}
+
+	assert.True(t, tlsAlert(alert))
+	assert.False(t, tlsAlert(unknownAuthority))
+	assert.False(t, tlsAlert(plainHTTP))
+	assert.False(t, tlsAlert(dial))
+	assert.False(t, tlsAlert(timeout))
+	assert.False(t, tlsAlert(nil))
+}
+
+func TestServerVerificationError(t *testing.T) {
+	unknownAuthority := &url.Error{Op: "Get", URL: "https://as", Err: x509.UnknownAuthorityError{}}
+	hostname := &url.Error{Op: "Get", URL: "https://as", Err: x509.HostnameError{Certificate: &x509.Certificate{}}}
+	alert := &url.Error{Op: "Get", URL: "https://as", Err: errors.New("remote error: tls: bad certificate")}
+	dial := &url.Error{Op: "Get", URL: "https://as", Err: errors.New("dial tcp: lookup as: no such host")}
+
+	assert.True(t, serverVerificationError(unknownAuthority))
+	assert.True(t, serverVerificationError(hostname))
+	assert.False(t, serverVerificationError(alert))
+	assert.False(t, serverVerificationError(dial))
+	assert.False(t, serverVerificationError(nil))
+}
@@ -1,22 +1,49 @@
 package compliant
 
 import (
-	"errors"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"strings"
+
 	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
 )
 
 // resolves what token signing method to use based on .wellknown and supported
-func responseTokenSignMethod(methods *[]string) (jwt.SigningMethod, error) {
+// signing algorithms for the signing key type, in order of preference
+func responseTokenSignMethod(methods *[]string, privateKey crypto.Signer) (jwt.SigningMethod, error) {
+	supported, err := keySigningMethods(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
 	if methods == nil {
-		return jwt.SigningMethodPS256, nil
+		return supported[0], nil
 	}
 
-	for _, value := range *methods {
-		// we only support PS256 at the moment
-		if value == "PS256" {
-			return jwt.SigningMethodPS256, nil
+	for _, method := range supported {
+		for _, value := range *methods {
+			if value == method.Alg() {
+				return method, nil
+			}
 		}
 	}
 
-	return nil, errors.New("PS256 token sign method not found")
+	algs := make([]string, 0, len(supported))
+	for _, method := range supported {
+		algs = append(algs, method.Alg())
+	}
+	return nil, errors.Errorf("token sign method not found, signing key supports %s", strings.Join(algs, ", "))
+}
+
+func keySigningMethods(privateKey crypto.Signer) ([]jwt.SigningMethod, error) {
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		return []jwt.SigningMethod{jwt.SigningMethodPS256, jwt.SigningMethodPS384, jwt.SigningMethodPS512}, nil
+	case *ecdsa.PrivateKey:
+		return []jwt.SigningMethod{jwt.SigningMethodES256}, nil
+	default:
+		return nil, errors.Errorf("unsupported signing key type %T", privateKey)
+	}
 }
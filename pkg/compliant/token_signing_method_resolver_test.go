@@ -1,25 +1,69 @@
 package compliant
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResponseTokenSignMethod(t *testing.T) {
-	method, err := responseTokenSignMethod(nil)
+	rsaKey := &rsa.PrivateKey{}
+
+	method, err := responseTokenSignMethod(nil, rsaKey)
 	assert.NoError(t, err)
 	assert.Equal(t, method, jwt.SigningMethodPS256)
 
-	method, err = responseTokenSignMethod(&[]string{"PS256"})
+	method, err = responseTokenSignMethod(&[]string{"PS256"}, rsaKey)
 	assert.NoError(t, err)
 	assert.Equal(t, method, jwt.SigningMethodPS256)
 
-	method, err = responseTokenSignMethod(&[]string{"RS256", "PS256"})
+	method, err = responseTokenSignMethod(&[]string{"RS256", "PS256"}, rsaKey)
 	assert.NoError(t, err)
 	assert.Equal(t, method, jwt.SigningMethodPS256)
 
-	method, err = responseTokenSignMethod(&[]string{"RS256"})
-	assert.EqualError(t, err, "PS256 token sign method not found")
+	method, err = responseTokenSignMethod(&[]string{"RS256"}, rsaKey)
+	assert.EqualError(t, err, "token sign method not found, signing key supports PS256, PS384, PS512")
+	assert.Nil(t, method)
+}
+
+func TestResponseTokenSignMethod_RSAStrongerPSS(t *testing.T) {
+	rsaKey := &rsa.PrivateKey{}
+
+	method, err := responseTokenSignMethod(&[]string{"PS512", "PS384"}, rsaKey)
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodPS384, method)
+
+	method, err = responseTokenSignMethod(&[]string{"ES256", "PS512"}, rsaKey)
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodPS512, method)
+}
+
+func TestResponseTokenSignMethod_ECKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	method, err := responseTokenSignMethod(nil, ecKey)
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodES256, method)
+
+	method, err = responseTokenSignMethod(&[]string{"PS256", "ES256"}, ecKey)
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodES256, method)
+
+	method, err = responseTokenSignMethod(&[]string{"PS256"}, ecKey)
+	assert.EqualError(t, err, "token sign method not found, signing key supports ES256")
+	assert.Nil(t, method)
+}
+
+func TestResponseTokenSignMethod_UnsupportedKey(t *testing.T) {
+	method, err := responseTokenSignMethod(nil, nil)
+
+	assert.EqualError(t, err, "unsupported signing key type <nil>")
 	assert.Nil(t, method)
 }
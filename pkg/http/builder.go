@@ -3,6 +3,7 @@ package http
 import (
 	"crypto/tls"
 	"github.com/pkg/errors"
+	"io"
 	"net/http"
 )
 
@@ -16,6 +17,12 @@ type mTLSClientBuilder struct {
 	certPEMBlock, keyPEMBlock *string
 	rootCAs                   *[]string
 	tlsSkipVerify             bool
+	noClientCert              bool
+	tlsMaxVersion             uint16
+	cipherSuites              []uint16
+	network                   NetworkConfig
+	recorder                  *Recorder
+	keyLogWriter              io.Writer
 }
 
 func NewBuilder() *mTLSClientBuilder {
@@ -24,6 +31,12 @@ func NewBuilder() *mTLSClientBuilder {
 		keyPEMBlock:   nil,
 		rootCAs:       nil,
 		tlsSkipVerify: false,
+		noClientCert:  false,
+		tlsMaxVersion: 0,
+		cipherSuites:  nil,
+		network:       NetworkConfig{},
+		recorder:      nil,
+		keyLogWriter:  nil,
 	}
 }
 
@@ -37,6 +50,35 @@ func (b *mTLSClientBuilder) WithTlsSkipVerify(tlsSkipVerify bool) *mTLSClientBui
 	return b
 }
 
+// WithoutClientCert builds a client that doesn't present a certificate, ex: to check the server requires one
+func (b *mTLSClientBuilder) WithoutClientCert() *mTLSClientBuilder {
+	b.noClientCert = true
+	return b
+}
+
+// WithCipherSuites restricts the client to TLS 1.2 and the cipher suites, TLS 1.3 suites are not configurable
+func (b *mTLSClientBuilder) WithCipherSuites(cipherSuites []uint16) *mTLSClientBuilder {
+	b.tlsMaxVersion = tls.VersionTLS12
+	b.cipherSuites = cipherSuites
+	return b
+}
+
+func (b *mTLSClientBuilder) WithNetworkConfig(network NetworkConfig) *mTLSClientBuilder {
+	b.network = network
+	return b
+}
+
+func (b *mTLSClientBuilder) WithRecorder(recorder *Recorder) *mTLSClientBuilder {
+	b.recorder = recorder
+	return b
+}
+
+// WithKeyLogWriter writes the TLS session keys of the client connections, exposing the traffic to anyone reading them
+func (b *mTLSClientBuilder) WithKeyLogWriter(keyLogWriter io.Writer) *mTLSClientBuilder {
+	b.keyLogWriter = keyLogWriter
+	return b
+}
+
 func (b *mTLSClientBuilder) WithTransportKeyPair(certPEMBlock, keyPEMBlock string) *mTLSClientBuilder {
 	b.certPEMBlock = &certPEMBlock
 	b.keyPEMBlock = &keyPEMBlock
@@ -44,13 +86,17 @@ func (b *mTLSClientBuilder) WithTransportKeyPair(certPEMBlock, keyPEMBlock strin
 }
 
 func (b *mTLSClientBuilder) Build() (*http.Client, error) {
-	if b.certPEMBlock == nil || b.keyPEMBlock == nil {
-		return nil, errors.New("can't build a mtls client without cert and key")
-	}
+	var clientCerts []tls.Certificate
+	if !b.noClientCert {
+		if b.certPEMBlock == nil || b.keyPEMBlock == nil {
+			return nil, errors.New("can't build a mtls client without cert and key")
+		}
 
-	clientCerts, err := TlsClientCert([]byte(*b.certPEMBlock), []byte(*b.keyPEMBlock))
-	if err != nil {
-		return nil, errors.Wrap(err, "building mTLS http client")
+		var err error
+		clientCerts, err = TlsClientCert([]byte(*b.certPEMBlock), []byte(*b.keyPEMBlock))
+		if err != nil {
+			return nil, errors.Wrap(err, "building mTLS http client")
+		}
 	}
 
 	if b.rootCAs == nil {
@@ -67,6 +113,11 @@ func (b *mTLSClientBuilder) Build() (*http.Client, error) {
 		InsecureSkipVerify: b.tlsSkipVerify,
 		RootCAs:            rootCAs,
 		TLSMinVersion:      tls.VersionTLS12,
+		TLSMaxVersion:      b.tlsMaxVersion,
+		CipherSuites:       b.cipherSuites,
+		Network:            b.network,
+		Recorder:           b.recorder,
+		KeyLogWriter:       b.keyLogWriter,
 	}
 
 	return NewMATLSClient(config)
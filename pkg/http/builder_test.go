@@ -86,3 +86,16 @@ func TestNewBuilder_ErrorsIfRootCAIsInvalid(t *testing.T) {
 	)
 	assert.Nil(t, client)
 }
+
+func TestNewBuilder_WithoutClientCert(t *testing.T) {
+	rootCA, err := ioutil.ReadFile("testdata/client-sample-root-ca.pem")
+	require.NoError(t, err)
+
+	client, err := NewBuilder().
+		WithoutClientCert().
+		WithRootCAs([]string{string(rootCA)}).
+		Build()
+
+	assert.NoError(t, err)
+	assert.IsType(t, &http.Client{}, client)
+}
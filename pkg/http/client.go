@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"github.com/pkg/errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"time"
@@ -15,6 +16,14 @@ type MATLSConfig struct {
 	InsecureSkipVerify bool
 	RootCAs            []*x509.Certificate
 	TLSMinVersion      uint16
+	// TLSMaxVersion and CipherSuites restrict the handshake, ex: to probe cipher suites the server should reject
+	TLSMaxVersion uint16
+	CipherSuites  []uint16
+	Network       NetworkConfig
+	// Recorder captures the client traffic when set
+	Recorder *Recorder
+	// KeyLogWriter receives the TLS session keys in NSS key log format when set, ie: to decrypt captures in Wireshark
+	KeyLogWriter io.Writer
 }
 
 // NewMATLSClient creates a new http client that is configured for Mutually Authenticated TLS. `insecureSkipVerify`
@@ -26,8 +35,11 @@ func NewMATLSClient(config MATLSConfig) (*http.Client, error) {
 	tlsConfig := &tls.Config{
 		Certificates:       config.ClientCerts,
 		MinVersion:         config.TLSMinVersion,
+		MaxVersion:         config.TLSMaxVersion,
+		CipherSuites:       config.CipherSuites,
 		Renegotiation:      tls.RenegotiateFreelyAsClient,
 		InsecureSkipVerify: config.InsecureSkipVerify,
+		KeyLogWriter:       config.KeyLogWriter,
 	}
 
 	if len(config.RootCAs) > 0 {
@@ -36,8 +48,19 @@ func NewMATLSClient(config MATLSConfig) (*http.Client, error) {
 
 	tlsConfig.BuildNameToCertificate()
 	transport := &http.Transport{TLSClientConfig: tlsConfig}
+	if err := config.Network.configure(transport); err != nil {
+		return nil, errors.Wrap(err, "building mTLS http client")
+	}
+
+	// the recorder is the innermost round tripper so it captures the headers added by the others
+	var roundTripper http.RoundTripper = transport
+	if config.Recorder != nil {
+		roundTripper = config.Recorder.Transport(roundTripper)
+	}
+	// default headers wrap the interaction id generation so a configured interaction id is kept
+	roundTripper = config.Network.withHeaders(interactionIDTransport{next: roundTripper})
 
-	return &http.Client{Transport: transport, Timeout: time.Second * 10}, nil
+	return &http.Client{Transport: roundTripper, Timeout: time.Second * 10}, nil
 }
 
 func TlsClientCert(certPEMBlock, keyPEMBlock []byte) ([]tls.Certificate, error) {
@@ -1,11 +1,13 @@
 package http
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -77,8 +79,10 @@ func TestNewMATLSClient(t *testing.T) {
 	got, err := NewMATLSClient(config)
 	require.NoError(t, err)
 
-	trsActual, ok := got.Transport.(*http.Transport)
-	assert.True(t, ok)
+	interactionTransport, ok := got.Transport.(interactionIDTransport)
+	require.True(t, ok)
+	trsActual, ok := interactionTransport.next.(*http.Transport)
+	require.True(t, ok)
 	trsExpected, ok := wantClient.Transport.(*http.Transport)
 	assert.True(t, ok)
 
@@ -91,3 +95,20 @@ func TestNewMATLSClient(t *testing.T) {
 	actualRootCAs := trsActual.TLSClientConfig.RootCAs.Subjects()
 	assert.Equal(t, expectedRootCAs, actualRootCAs)
 }
+
+func TestNewMATLSClient_WritesTLSKeyLog(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	keyLog := &bytes.Buffer{}
+
+	client, err := NewMATLSClient(MATLSConfig{
+		RootCAs:      []*x509.Certificate{server.Certificate()},
+		KeyLogWriter: keyLog,
+	})
+	require.NoError(t, err)
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Contains(t, keyLog.String(), "CLIENT_TRAFFIC_SECRET_0 ")
+}
@@ -14,10 +14,14 @@ func DebugRequest(req *http.Request) string {
 	return fmt.Sprintf("request:\n %s", string(debug))
 }
 
+// DebugResponse dumps a response, followed by the TLS handshake details of its connection when made over TLS
 func DebugResponse(r *http.Response) string {
 	debug, err := httputil.DumpResponse(r, true)
 	if err != nil {
 		return fmt.Sprintf("cant debug response object: %s", err.Error())
 	}
+	if r.TLS != nil {
+		return fmt.Sprintf("response:\n %s\ntls: %s", string(debug), NewTLSConnection(r.TLS))
+	}
 	return fmt.Sprintf("response:\n %s", string(debug))
 }
@@ -0,0 +1,379 @@
+package http
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/redact"
+)
+
+// HAR 1.2 archive of http traffic, loads in browser devtools
+// http://www.softwareishard.com/blog/har-12-spec/
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is a request and response pair, `_tls` and `_error` are custom fields as allowed by the spec
+type HAREntry struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	Time            float64        `json:"time"`
+	Request         HARRequest     `json:"request"`
+	Response        HARResponse    `json:"response"`
+	Cache           struct{}       `json:"cache"`
+	Timings         HARTimings     `json:"timings"`
+	TLS             *TLSConnection `json:"_tls,omitempty"`
+	Error           string         `json:"_error,omitempty"`
+}
+
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARTimings are in milliseconds, -1 when the phase doesn't apply, ex: no dns lookup on a reused connection
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// Redacted is a copy of the entry with secrets masked in headers, query strings and bodies
+func (e HAREntry) Redacted() HAREntry {
+	redacted := e
+	redacted.Request.URL = redact.String(e.Request.URL)
+	redacted.Request.Headers = redactedHeaders(e.Request.Headers)
+	redacted.Request.QueryString = redactedQueryString(e.Request.QueryString)
+	if e.Request.PostData != nil {
+		redacted.Request.PostData = &HARPostData{
+			MimeType: e.Request.PostData.MimeType,
+			Text:     redact.String(e.Request.PostData.Text),
+		}
+	}
+	redacted.Response.Headers = redactedHeaders(e.Response.Headers)
+	redacted.Response.Content.Text = redact.String(e.Response.Content.Text)
+	redacted.Error = redact.String(e.Error)
+	return redacted
+}
+
+func redactedHeaders(headers []HARNameValue) []HARNameValue {
+	redacted := make([]HARNameValue, len(headers))
+	for key, header := range headers {
+		redacted[key] = HARNameValue{Name: header.Name, Value: redact.Header(header.Name, header.Value)}
+	}
+	return redacted
+}
+
+func redactedQueryString(query []HARNameValue) []HARNameValue {
+	redacted := make([]HARNameValue, len(query))
+	for key, param := range query {
+		value := redact.String(param.Name + "=" + param.Value)
+		redacted[key] = HARNameValue{Name: param.Name, Value: strings.TrimPrefix(value, param.Name+"=")}
+	}
+	return redacted
+}
+
+const (
+	harVersion     = "1.2"
+	harCreatorName = "conformance-dcr"
+)
+
+// NewHAR archives the entries recorded for a scenario
+func NewHAR(entries []HAREntry) HAR {
+	return HAR{
+		Log: HARLog{
+			Version: harVersion,
+			Creator: HARCreator{Name: harCreatorName, Version: harVersion},
+			Entries: entries,
+		},
+	}
+}
+
+// Recorder captures the requests and responses of the clients it's the transport of
+type Recorder struct {
+	mu      sync.Mutex
+	entries []HAREntry
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Transport wraps a round tripper to record its traffic
+func (r *Recorder) Transport(next http.RoundTripper) http.RoundTripper {
+	return recorderTransport{next: next, recorder: r}
+}
+
+// Take returns the entries recorded since the previous take
+func (r *Recorder) Take() []HAREntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := r.entries
+	r.entries = nil
+	return entries
+}
+
+func (r *Recorder) add(entry HAREntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+type recorderTransport struct {
+	next     http.RoundTripper
+	recorder *Recorder
+}
+
+func (t recorderTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	timer := &harTimer{}
+	// a round tripper must not modify the request
+	r = r.Clone(httptrace.WithClientTrace(r.Context(), timer.trace()))
+
+	var requestBody []byte
+	if r.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	start := time.Now()
+	response, err := t.next.RoundTrip(r)
+	entry := HAREntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Request:         harRequest(r, requestBody),
+	}
+	if err != nil {
+		entry.Time = milliseconds(time.Since(start))
+		entry.Timings = timer.timings(start, time.Now())
+		entry.Error = err.Error()
+		t.recorder.add(entry)
+		return nil, err
+	}
+
+	responseBody, readErr := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+	end := time.Now()
+
+	entry.Time = milliseconds(end.Sub(start))
+	entry.Response = harResponse(response, responseBody)
+	entry.Timings = timer.timings(start, end)
+	entry.TLS = harTLS(response.TLS)
+	if readErr != nil {
+		// a round tripper returns either a response or an error, the partial response is only recorded
+		entry.Error = readErr.Error()
+		t.recorder.add(entry)
+		return nil, readErr
+	}
+	t.recorder.add(entry)
+
+	return response, nil
+}
+
+func harRequest(r *http.Request, body []byte) HARRequest {
+	request := HARRequest{
+		Method:      r.Method,
+		URL:         r.URL.String(),
+		HTTPVersion: r.Proto,
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(r.Header),
+		QueryString: []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	for name, values := range r.URL.Query() {
+		for _, value := range values {
+			request.QueryString = append(request.QueryString, HARNameValue{Name: name, Value: value})
+		}
+	}
+	if len(body) > 0 {
+		request.PostData = &HARPostData{MimeType: r.Header.Get("Content-Type"), Text: string(body)}
+	}
+	return request
+}
+
+func harResponse(r *http.Response, body []byte) HARResponse {
+	return HARResponse{
+		Status:      r.StatusCode,
+		StatusText:  http.StatusText(r.StatusCode),
+		HTTPVersion: r.Proto,
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(r.Header),
+		Content: HARContent{
+			Size:     len(body),
+			MimeType: r.Header.Get("Content-Type"),
+			Text:     string(body),
+		},
+		RedirectURL: r.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+func harHeaders(header http.Header) []HARNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := []HARNameValue{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			headers = append(headers, HARNameValue{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+func harTLS(state *tls.ConnectionState) *TLSConnection {
+	if state == nil {
+		return nil
+	}
+	connection := NewTLSConnection(state)
+	return &connection
+}
+
+// TLSVersionName is the name of a tls version, ex: `TLS 1.2`
+func TLSVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return "unknown"
+}
+
+// harTimer collects the connection phase times of a request with a client trace
+type harTimer struct {
+	mu                               sync.Mutex
+	dnsStart, dnsDone                time.Time
+	connectStart, connectDone        time.Time
+	tlsStart, tlsDone                time.Time
+	gotConn, wroteRequest, firstByte time.Time
+}
+
+func (h *harTimer) trace() *httptrace.ClientTrace {
+	set := func(t *time.Time) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		*t = time.Now()
+	}
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { set(&h.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { set(&h.dnsDone) },
+		ConnectStart:         func(string, string) { set(&h.connectStart) },
+		ConnectDone:          func(string, string, error) { set(&h.connectDone) },
+		TLSHandshakeStart:    func() { set(&h.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&h.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { set(&h.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { set(&h.wroteRequest) },
+		GotFirstResponseByte: func() { set(&h.firstByte) },
+	}
+}
+
+func (h *harTimer) timings(start, end time.Time) HARTimings {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	timings := HARTimings{
+		Blocked: -1,
+		DNS:     phase(h.dnsStart, h.dnsDone),
+		Connect: phase(h.connectStart, h.connectDone),
+		SSL:     phase(h.tlsStart, h.tlsDone),
+		Send:    phase(h.gotConn, h.wroteRequest),
+		Wait:    phase(h.wroteRequest, h.firstByte),
+		Receive: phase(h.firstByte, end),
+	}
+	// HAR connect time includes the ssl time
+	if timings.Connect >= 0 && timings.SSL >= 0 {
+		timings.Connect += timings.SSL
+	}
+	if !h.gotConn.IsZero() {
+		timings.Blocked = milliseconds(h.gotConn.Sub(start)) - nonNegative(timings.DNS) - nonNegative(timings.Connect)
+		timings.Blocked = nonNegative(timings.Blocked)
+	}
+	return timings
+}
+
+func phase(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return -1
+	}
+	return milliseconds(end.Sub(start))
+}
+
+func nonNegative(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordsRequestAndResponse(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "grant_type=client_credentials", string(body))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusCreated)
+		_, err = rw.Write([]byte(`{"client_id": "id"}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	recorder := NewRecorder()
+	client := &http.Client{Transport: recorder.Transport(server.Client().Transport)}
+	r, err := http.NewRequest(
+		http.MethodPost,
+		server.URL+"/token?scope=accounts",
+		strings.NewReader("grant_type=client_credentials"),
+	)
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := client.Do(r)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"client_id": "id"}`, string(body), "response body should still be readable")
+	entries := recorder.Take()
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, http.MethodPost, entry.Request.Method)
+	assert.Equal(t, server.URL+"/token?scope=accounts", entry.Request.URL)
+	assert.Equal(t, []HARNameValue{{Name: "scope", Value: "accounts"}}, entry.Request.QueryString)
+	assert.Equal(t, &HARPostData{
+		MimeType: "application/x-www-form-urlencoded",
+		Text:     "grant_type=client_credentials",
+	}, entry.Request.PostData)
+	assert.Equal(t, http.StatusCreated, entry.Response.Status)
+	assert.Equal(t, `{"client_id": "id"}`, entry.Response.Content.Text)
+	assert.Equal(t, "application/json", entry.Response.Content.MimeType)
+	require.NotNil(t, entry.TLS)
+	assert.NotEqual(t, "unknown", entry.TLS.Version)
+	assert.True(t, entry.Timings.Wait >= 0)
+	assert.Empty(t, recorder.Take(), "take should reset the recorded entries")
+}
+
+func TestRecorder_RecordsErrors(t *testing.T) {
+	recorder := NewRecorder()
+	client := &http.Client{Transport: recorder.Transport(&http.Transport{})}
+
+	_, err := client.Get("http://127.0.0.1:0")
+
+	assert.Error(t, err)
+	entries := recorder.Take()
+	require.Len(t, entries, 1)
+	assert.NotEmpty(t, entries[0].Error)
+	assert.Equal(t, 0, entries[0].Response.Status)
+}
+
+func TestRecorder_RecordsResponseBodyReadErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Length", "100")
+		_, err := rw.Write([]byte("partial"))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	recorder := NewRecorder()
+	client := &http.Client{Transport: recorder.Transport(server.Client().Transport)}
+
+	response, err := client.Get(server.URL)
+
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	entries := recorder.Take()
+	require.Len(t, entries, 1)
+	assert.NotEmpty(t, entries[0].Error)
+	assert.Equal(t, http.StatusOK, entries[0].Response.Status)
+	assert.Equal(t, "partial", entries[0].Response.Content.Text)
+}
+
+func TestNewHAR(t *testing.T) {
+	har := NewHAR([]HAREntry{{StartedDateTime: "now"}})
+
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, "conformance-dcr", har.Log.Creator.Name)
+	assert.Len(t, har.Log.Entries, 1)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// InteractionIDHeader correlates a request with the ASPSP logs, the ASPSP should echo it back in the response
+const InteractionIDHeader = "x-fapi-interaction-id"
+
+// InteractionID returns the interaction id sent with the request of a response
+func InteractionID(r *http.Response) string {
+	if r == nil || r.Request == nil {
+		return ""
+	}
+	return r.Request.Header.Get(InteractionIDHeader)
+}
+
+// interactionIDTransport sets a new UUID interaction id on each request that doesn't already have one
+type interactionIDTransport struct {
+	next http.RoundTripper
+}
+
+func (t interactionIDTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Header.Get(InteractionIDHeader) != "" {
+		return t.next.RoundTrip(r)
+	}
+
+	// a round tripper must not modify the request
+	r = r.Clone(r.Context())
+	r.Header.Set(InteractionIDHeader, uuid.New().String())
+	return t.next.RoundTrip(r)
+}
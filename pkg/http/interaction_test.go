@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInteractionIDTransport_SetsInteractionID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set(InteractionIDHeader, r.Header.Get(InteractionIDHeader))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: interactionIDTransport{next: &http.Transport{}}}
+
+	response, err := client.Get(server.URL)
+
+	require.NoError(t, err)
+	defer response.Body.Close()
+	_, err = uuid.Parse(InteractionID(response))
+	assert.NoError(t, err)
+	assert.Equal(t, InteractionID(response), response.Header.Get(InteractionIDHeader))
+}
+
+func TestInteractionIDTransport_KeepsRequestInteractionID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "interaction-id", r.Header.Get(InteractionIDHeader))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: interactionIDTransport{next: &http.Transport{}}}
+	r, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	r.Header.Set(InteractionIDHeader, "interaction-id")
+
+	response, err := client.Do(r)
+
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, "interaction-id", InteractionID(response))
+}
+
+func TestInteractionID_EmptyWithoutRequest(t *testing.T) {
+	assert.Equal(t, "", InteractionID(&http.Response{}))
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NetworkConfig routes the requests of a client through a proxy and to mapped hosts, adding default headers.
+// The zero value makes direct requests without extra headers.
+type NetworkConfig struct {
+	// ProxyURL is the http proxy for all requests, ex: `http://proxy.example.com:3128`
+	ProxyURL string
+	// NoProxy is a comma separated list of hosts, domains (`.example.com`) and CIDRs not to proxy, `*` disables the proxy
+	NoProxy string
+	// Headers are added to every request that doesn't already set them
+	Headers map[string]string
+	// Hosts maps host names to the IP address to connect to, for environments without DNS records
+	Hosts map[string]string
+}
+
+// Transport configures a transport with the proxy and mapped hosts, and wraps it to add the default headers
+func (c NetworkConfig) Transport(transport *http.Transport) (http.RoundTripper, error) {
+	if err := c.configure(transport); err != nil {
+		return nil, err
+	}
+	return c.withHeaders(transport), nil
+}
+
+// configure routes the transport connections through the proxy and to the mapped hosts
+func (c NetworkConfig) configure(transport *http.Transport) error {
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return errors.Wrap(err, "parsing proxy url")
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return errors.Errorf("parsing proxy url: %s is not an absolute url", c.ProxyURL)
+		}
+		transport.Proxy = func(r *http.Request) (*url.URL, error) {
+			if noProxy(c.NoProxy, r.URL.Host) {
+				return nil, nil
+			}
+			return proxyURL, nil
+		}
+	}
+
+	if len(c.Hosts) > 0 {
+		dialer := &net.Dialer{}
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, mappedAddress(c.Hosts, addr))
+		}
+	}
+
+	return nil
+}
+
+// withHeaders wraps a round tripper to add the default headers
+func (c NetworkConfig) withHeaders(next http.RoundTripper) http.RoundTripper {
+	if len(c.Headers) == 0 {
+		return next
+	}
+	return headerTransport{next: next, headers: c.Headers}
+}
+
+// noProxy is true when the request host matches an entry of the comma separated no proxy list
+func noProxy(noProxyList, host string) bool {
+	hostname := strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = h
+	}
+
+	for _, entry := range strings.Split(strings.ToLower(noProxyList), ",") {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case entry == "*", entry == hostname, entry == strings.ToLower(host):
+			return true
+		case strings.HasSuffix(hostname, "."+strings.TrimPrefix(entry, ".")):
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip := net.ParseIP(hostname); ip != nil && network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mappedAddress replaces the host of a `host:port` address when it's mapped to an IP address
+func mappedAddress(hosts map[string]string, addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	ip, ok := hosts[host]
+	if !ok {
+		return addr
+	}
+	return net.JoinHostPort(ip, port)
+}
+
+type headerTransport struct {
+	next    http.RoundTripper
+	headers map[string]string
+}
+
+func (t headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// a round tripper must not modify the request
+	r = r.Clone(r.Context())
+	for name, value := range t.headers {
+		if r.Header.Get(name) == "" {
+			r.Header.Set(name, value)
+		}
+	}
+	return t.next.RoundTrip(r)
+}
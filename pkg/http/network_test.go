@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkConfig_AddsDefaultHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "financial-id", r.Header.Get("x-fapi-financial-id"))
+		assert.Equal(t, "request-value", r.Header.Get("x-custom"))
+	}))
+	defer server.Close()
+	network := NetworkConfig{
+		Headers: map[string]string{"x-fapi-financial-id": "financial-id", "x-custom": "default-value"},
+	}
+	transport, err := network.Transport(&http.Transport{})
+	require.NoError(t, err)
+	r, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	r.Header.Set("x-custom", "request-value")
+
+	response, err := (&http.Client{Transport: transport}).Do(r)
+
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Empty(t, r.Header.Get("x-fapi-financial-id"), "request should not be modified")
+}
+
+func TestNetworkConfig_MapsHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Host, "bank.invalid")
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	transport, err := NetworkConfig{Hosts: map[string]string{"bank.invalid": host}}.Transport(&http.Transport{})
+	require.NoError(t, err)
+
+	response, err := (&http.Client{Transport: transport}).Get("http://bank.invalid:" + port)
+
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestNetworkConfig_UsesProxy(t *testing.T) {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		proxied = true
+		assert.Equal(t, "http://bank.invalid/register", r.URL.String())
+	}))
+	defer proxy.Close()
+	transport, err := NetworkConfig{ProxyURL: proxy.URL}.Transport(&http.Transport{})
+	require.NoError(t, err)
+
+	response, err := (&http.Client{Transport: transport}).Get("http://bank.invalid/register")
+
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.True(t, proxied)
+}
+
+func TestNetworkConfig_FailsOnRelativeProxyURL(t *testing.T) {
+	_, err := NetworkConfig{ProxyURL: "proxy.example.com"}.Transport(&http.Transport{})
+
+	assert.EqualError(t, err, "parsing proxy url: proxy.example.com is not an absolute url")
+}
+
+func TestNoProxy(t *testing.T) {
+	noProxyList := "localhost, .internal.example.com,sandbox.example.com:8443,10.0.0.0/8"
+
+	assert.True(t, noProxy(noProxyList, "localhost:443"))
+	assert.True(t, noProxy(noProxyList, "as.internal.example.com"))
+	assert.True(t, noProxy(noProxyList, "sandbox.example.com:8443"))
+	assert.True(t, noProxy(noProxyList, "10.1.2.3:443"))
+	assert.False(t, noProxy(noProxyList, "sandbox.example.com:443"))
+	assert.False(t, noProxy(noProxyList, "bank.example.com"))
+	assert.True(t, noProxy("*", "bank.example.com"))
+}
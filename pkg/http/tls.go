@@ -0,0 +1,107 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TLSConnection describes the TLS handshake of a connection, ie: negotiated version and cipher suite
+// and the certificate chain presented by the server
+type TLSConnection struct {
+	Version      string           `json:"version"`
+	CipherSuite  string           `json:"cipherSuite"`
+	ServerName   string           `json:"serverName"`
+	Certificates []TLSCertificate `json:"certificates,omitempty"`
+}
+
+// TLSCertificate is a server certificate of the chain, leaf first
+type TLSCertificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dnsNames,omitempty"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+}
+
+// NewTLSConnection reads the handshake details of a connection state, ex: `response.TLS`
+func NewTLSConnection(state *tls.ConnectionState) TLSConnection {
+	connection := TLSConnection{
+		Version:     TLSVersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	for _, cert := range state.PeerCertificates {
+		connection.Certificates = append(connection.Certificates, newTLSCertificate(cert))
+	}
+	return connection
+}
+
+func newTLSCertificate(cert *x509.Certificate) TLSCertificate {
+	return TLSCertificate{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		DNSNames:  cert.DNSNames,
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+	}
+}
+
+// String is a multi line description of the connection for debug logs
+func (c TLSConnection) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%s %s server name: %s", c.Version, c.CipherSuite, c.ServerName))
+	for key, cert := range c.Certificates {
+		sb.WriteString(fmt.Sprintf(
+			"\ncertificate %d: subject: %s issuer: %s SANs: %s expires: %s",
+			key,
+			cert.Subject,
+			cert.Issuer,
+			strings.Join(cert.DNSNames, ", "),
+			cert.NotAfter.Format(time.RFC3339),
+		))
+	}
+	return sb.String()
+}
+
+// TLS 1.2 DHE suites are permitted by FAPI but not implemented by crypto/tls
+const (
+	cipherSuiteDHERSAWithAES128GCMSHA256 uint16 = 0x009e
+	cipherSuiteDHERSAWithAES256GCMSHA384 uint16 = 0x009f
+)
+
+// FAPICipherSuite is true for the cipher suites FAPI permits with TLS 1.2,
+// TLS 1.3 cipher suites are all permitted
+func FAPICipherSuite(cipherSuite uint16) bool {
+	switch cipherSuite {
+	case cipherSuiteDHERSAWithAES128GCMSHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		cipherSuiteDHERSAWithAES256GCMSHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:
+		return true
+	}
+	return false
+}
+
+// NonFAPICipherSuites are the TLS 1.2 cipher suites implemented by crypto/tls that FAPI doesn't permit
+func NonFAPICipherSuites() []uint16 {
+	var cipherSuites []uint16
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if FAPICipherSuite(suite.ID) || !supportsTLS12(suite) {
+			continue
+		}
+		cipherSuites = append(cipherSuites, suite.ID)
+	}
+	return cipherSuites
+}
+
+func supportsTLS12(suite *tls.CipherSuite) bool {
+	for _, version := range suite.SupportedVersions {
+		if version == tls.VersionTLS12 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSConnection(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	response, err := server.Client().Get(server.URL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	connection := NewTLSConnection(response.TLS)
+
+	assert.Equal(t, "TLS 1.3", connection.Version)
+	assert.Equal(t, tls.CipherSuiteName(response.TLS.CipherSuite), connection.CipherSuite)
+	require.Len(t, connection.Certificates, 1)
+	assert.Equal(t, "O=Acme Co", connection.Certificates[0].Subject)
+	assert.Equal(t, []string{"example.com", "*.example.com"}, connection.Certificates[0].DNSNames)
+	assert.Equal(t, response.TLS.PeerCertificates[0].NotAfter, connection.Certificates[0].NotAfter)
+	assert.Contains(
+		t,
+		connection.String(),
+		"certificate 0: subject: O=Acme Co issuer: O=Acme Co SANs: example.com, *.example.com",
+	)
+}
+
+func TestFAPICipherSuite(t *testing.T) {
+	assert.True(t, FAPICipherSuite(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256))
+	assert.True(t, FAPICipherSuite(tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384))
+	assert.False(t, FAPICipherSuite(tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256))
+	assert.False(t, FAPICipherSuite(tls.TLS_RSA_WITH_AES_128_GCM_SHA256))
+}
+
+func TestNonFAPICipherSuites(t *testing.T) {
+	cipherSuites := NonFAPICipherSuites()
+
+	assert.Contains(t, cipherSuites, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256)
+	assert.Contains(t, cipherSuites, tls.TLS_RSA_WITH_AES_128_GCM_SHA256)
+	assert.NotContains(t, cipherSuites, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+	assert.NotContains(t, cipherSuites, tls.TLS_AES_128_GCM_SHA256)
+}
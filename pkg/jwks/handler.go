@@ -0,0 +1,26 @@
+package jwks
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type handler struct {
+	set Set
+}
+
+// NewHandler serves a JSON Web Key Set, to be published as a `jwks_uri`
+func NewHandler(set Set) http.Handler {
+	return handler{set: set}
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.set); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
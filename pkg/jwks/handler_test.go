@@ -0,0 +1,32 @@
+package jwks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ServesKeySet(t *testing.T) {
+	set := NewSet(JWK{Kty: "RSA", Kid: "kid", N: "n", E: "AQAB"})
+	recorder := httptest.NewRecorder()
+
+	NewHandler(set).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/jwks", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var served Set
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&served))
+	assert.Equal(t, set, served)
+}
+
+func TestHandler_OnlyAllowsGet(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	NewHandler(NewSet()).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/jwks", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
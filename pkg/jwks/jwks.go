@@ -0,0 +1,87 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// JWK public JSON Web Key as defined in RFC7517
+type JWK struct {
+	Kty     string   `json:"kty"`
+	Kid     string   `json:"kid,omitempty"`
+	Use     string   `json:"use,omitempty"`
+	N       string   `json:"n,omitempty"`
+	E       string   `json:"e,omitempty"`
+	Crv     string   `json:"crv,omitempty"`
+	X       string   `json:"x,omitempty"`
+	Y       string   `json:"y,omitempty"`
+	X5c     []string `json:"x5c,omitempty"`
+	X5tS256 string   `json:"x5t#S256,omitempty"`
+}
+
+// Set JSON Web Key Set
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewSet(keys ...JWK) Set {
+	return Set{Keys: keys}
+}
+
+// NewJWK creates a JWK from a RSA or ECDSA public key
+func NewJWK(publicKey crypto.PublicKey, kid, use string) (JWK, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: use,
+			N:   encode(key.N.Bytes()),
+			E:   encode(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC",
+			Kid: kid,
+			Use: use,
+			Crv: key.Curve.Params().Name,
+			X:   encode(padded(key.X.Bytes(), size)),
+			Y:   encode(padded(key.Y.Bytes(), size)),
+		}, nil
+	}
+	return JWK{}, errors.Errorf("unsupported public key type %T", publicKey)
+}
+
+// NewCertificateJWK creates a JWK for the certificate public key including the x5c chain and x5t#S256 thumbprint
+func NewCertificateJWK(cert *x509.Certificate, kid, use string) (JWK, error) {
+	if cert == nil {
+		return JWK{}, errors.New("certificate is required to create jwk")
+	}
+	jwk, err := NewJWK(cert.PublicKey, kid, use)
+	if err != nil {
+		return JWK{}, errors.Wrap(err, "creating certificate jwk")
+	}
+	thumbprint := sha256.Sum256(cert.Raw)
+	jwk.X5c = []string{base64.StdEncoding.EncodeToString(cert.Raw)}
+	jwk.X5tS256 = encode(thumbprint[:])
+	return jwk, nil
+}
+
+func encode(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func padded(value []byte, size int) []byte {
+	if len(value) >= size {
+		return value
+	}
+	return append(make([]byte, size-len(value)), value...)
+}
@@ -0,0 +1,87 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJWK_RSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwk, err := NewJWK(key.Public(), "kid", "sig")
+	require.NoError(t, err)
+
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "kid", jwk.Kid)
+	assert.Equal(t, "sig", jwk.Use)
+	assert.Equal(t, "AQAB", jwk.E)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.N.Bytes()), jwk.N)
+}
+
+func TestNewJWK_EC(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := NewJWK(key.Public(), "kid", "sig")
+	require.NoError(t, err)
+
+	assert.Equal(t, "EC", jwk.Kty)
+	assert.Equal(t, "P-256", jwk.Crv)
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	require.NoError(t, err)
+	assert.Len(t, x, 32)
+	assert.Equal(t, 0, key.X.Cmp(new(big.Int).SetBytes(x)))
+}
+
+func TestNewJWK_UnsupportedKey(t *testing.T) {
+	_, err := NewJWK("not a key", "kid", "sig")
+
+	assert.EqualError(t, err, "unsupported public key type string")
+}
+
+func TestNewCertificateJWK(t *testing.T) {
+	cert := selfSignedCert(t)
+
+	jwk, err := NewCertificateJWK(cert, "kid", "tls")
+	require.NoError(t, err)
+
+	thumbprint := sha256.Sum256(cert.Raw)
+	assert.Equal(t, "EC", jwk.Kty)
+	assert.Equal(t, []string{base64.StdEncoding.EncodeToString(cert.Raw)}, jwk.X5c)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(thumbprint[:]), jwk.X5tS256)
+}
+
+func TestNewCertificateJWK_RequiresCertificate(t *testing.T) {
+	_, err := NewCertificateJWK(nil, "kid", "tls")
+
+	assert.EqualError(t, err, "certificate is required to create jwk")
+}
+
+func selfSignedCert(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
@@ -0,0 +1,88 @@
+package jwks
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const Path = "/jwks.json"
+
+// Server embeddable http server publishing a key set on Path,
+// usable by local test servers or exposed to sandboxes as a `jwks_uri`
+type Server struct {
+	addr     string
+	server   *http.Server
+	listener net.Listener
+	scheme   string
+	served   chan error
+}
+
+func NewServer(addr string, set Set) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(Path, NewHandler(set))
+	return &Server{
+		addr:   addr,
+		server: &http.Server{Handler: mux},
+		scheme: "http",
+	}
+}
+
+// Start listens on the server address and serves the key set in background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return errors.Wrap(err, "starting jwks server")
+	}
+	s.start(listener)
+	return nil
+}
+
+// StartTLS same as Start using https, most ASPSPs only accept https jwks uris
+func (s *Server) StartTLS(cert tls.Certificate) error {
+	listener, err := tls.Listen("tcp", s.addr, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	})
+	if err != nil {
+		return errors.Wrap(err, "starting jwks server")
+	}
+	s.scheme = "https"
+	s.start(listener)
+	return nil
+}
+
+func (s *Server) start(listener net.Listener) {
+	s.listener = listener
+	s.served = make(chan error, 1)
+	go func() {
+		err := s.server.Serve(listener)
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+		s.served <- errors.Wrap(err, "serving jwks")
+	}()
+}
+
+// URL of the key set once the server is started
+func (s *Server) URL() string {
+	if s.listener == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s%s", s.scheme, s.listener.Addr().String(), Path)
+}
+
+// Close shuts down the server, returning the error that stopped it from serving if any
+func (s *Server) Close() error {
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		return errors.Wrap(err, "closing jwks server")
+	}
+	if s.served == nil {
+		return nil
+	}
+	return <-s.served
+}
@@ -0,0 +1,68 @@
+package jwks
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ServesKeySet(t *testing.T) {
+	set := NewSet(JWK{Kty: "RSA", Kid: "kid", N: "n", E: "AQAB"})
+	server := NewServer("127.0.0.1:0", set)
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	response, err := http.Get(server.URL())
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	var served Set
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&served))
+	assert.Equal(t, set, served)
+}
+
+func TestServer_URLEmptyWhenNotStarted(t *testing.T) {
+	assert.Equal(t, "", NewServer("127.0.0.1:0", NewSet()).URL())
+}
+
+func TestServer_StartTLS(t *testing.T) {
+	cert, err := tls.LoadX509KeyPair("testdata/server-cert.pem", "testdata/server-key.pem")
+	require.NoError(t, err)
+	server := NewServer("127.0.0.1:0", NewSet())
+	require.NoError(t, server.StartTLS(cert))
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint:gosec
+	}}
+	response, err := client.Get(server.URL())
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, server.URL(), "https://127.0.0.1:")
+}
+
+func TestServer_CloseReturnsServeError(t *testing.T) {
+	server := NewServer("127.0.0.1:0", NewSet())
+	require.NoError(t, server.Start())
+	require.NoError(t, server.listener.Close())
+	for i := 0; i < 100 && len(server.served) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err := server.Close()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "serving jwks")
+}
+
+func TestServer_CloseWhenNotStarted(t *testing.T) {
+	assert.NoError(t, NewServer("127.0.0.1:0", NewSet()).Close())
+}
@@ -0,0 +1,29 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/x509"
+
+	"github.com/pkg/errors"
+)
+
+// NewTPPSet creates the key set a TPP publishes on the SSA `software_jwks_endpoint`:
+// the public part of the signing key identified by kid and the transport certificate
+// identified by its x5t#S256 thumbprint
+func NewTPPSet(signingKey crypto.Signer, kid string, transportCert *x509.Certificate) (Set, error) {
+	if signingKey == nil {
+		return Set{}, errors.New("signing key is required to create tpp jwks")
+	}
+	signing, err := NewJWK(signingKey.Public(), kid, "sig")
+	if err != nil {
+		return Set{}, errors.Wrap(err, "creating tpp jwks signing key")
+	}
+
+	transport, err := NewCertificateJWK(transportCert, "", "tls")
+	if err != nil {
+		return Set{}, errors.Wrap(err, "creating tpp jwks transport key")
+	}
+	transport.Kid = transport.X5tS256
+
+	return NewSet(signing, transport), nil
+}
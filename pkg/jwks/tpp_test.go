@@ -0,0 +1,43 @@
+package jwks
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTPPSet(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	transportCert := selfSignedCert(t)
+
+	set, err := NewTPPSet(signingKey, "signing-kid", transportCert)
+	require.NoError(t, err)
+
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "RSA", set.Keys[0].Kty)
+	assert.Equal(t, "signing-kid", set.Keys[0].Kid)
+	assert.Equal(t, "sig", set.Keys[0].Use)
+	assert.Empty(t, set.Keys[0].X5c)
+	assert.Equal(t, "tls", set.Keys[1].Use)
+	assert.Equal(t, set.Keys[1].X5tS256, set.Keys[1].Kid)
+	assert.Len(t, set.Keys[1].X5c, 1)
+}
+
+func TestNewTPPSet_RequiresTransportCert(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, err = NewTPPSet(signingKey, "signing-kid", nil)
+
+	assert.EqualError(t, err, "creating tpp jwks transport key: certificate is required to create jwk")
+}
+
+func TestNewTPPSet_RequiresSigningKey(t *testing.T) {
+	_, err := NewTPPSet(nil, "signing-kid", selfSignedCert(t))
+
+	assert.EqualError(t, err, "signing key is required to create tpp jwks")
+}
@@ -0,0 +1,59 @@
+// Package redact masks secrets in debug output: authorization credentials, client secrets, access tokens
+// and signatures of signed JWTs, so debug logs and reports can be shared.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mask replaces a redacted secret
+const Mask = "REDACTED"
+
+// nolint:gochecknoglobals
+var (
+	// authorization header credentials in http dumps, the scheme is kept
+	authorizationPattern = regexp.MustCompile(`(?im)^([ \t]*authorization:[ \t]*)(.*?)(\r?)$`)
+	// secrets as json properties
+	jsonSecretPattern = regexp.MustCompile(
+		`("(?:client_secret|access_token|refresh_token|registration_access_token)"\s*:\s*)"[^"]*"`,
+	)
+	// secrets as form or query parameters
+	formSecretPattern = regexp.MustCompile(
+		`\b((?:client_secret|access_token|refresh_token|registration_access_token)=)[^&\s"]*`,
+	)
+	// compact serialized JWS, the header and payload are kept as they help debugging
+	jwtPattern = regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*\.)[A-Za-z0-9_-]+`)
+)
+
+// String masks the secrets of a text, ex: an http request dump
+func String(s string) string {
+	s = authorizationPattern.ReplaceAllStringFunc(s, func(line string) string {
+		parts := authorizationPattern.FindStringSubmatch(line)
+		return parts[1] + Authorization(parts[2]) + parts[3]
+	})
+	s = jsonSecretPattern.ReplaceAllString(s, `${1}"`+Mask+`"`)
+	s = formSecretPattern.ReplaceAllString(s, "${1}"+Mask)
+	return jwtPattern.ReplaceAllString(s, "${1}"+Mask)
+}
+
+// Authorization masks the credentials of an authorization header value, ex: `Bearer REDACTED`
+func Authorization(value string) string {
+	fields := strings.Fields(value)
+	switch len(fields) {
+	case 0:
+		return value
+	case 1:
+		return Mask
+	default:
+		return fields[0] + " " + Mask
+	}
+}
+
+// Header masks the value of an http header when it holds credentials and secrets in other header values
+func Header(name, value string) string {
+	if strings.EqualFold(name, "authorization") {
+		return Authorization(value)
+	}
+	return String(value)
+}